@@ -0,0 +1,93 @@
+package config
+
+import (
+	"slices"
+	"strings"
+	"sync"
+
+	kmaps "github.com/knadh/koanf/maps"
+)
+
+// AccessLog records which configuration keys are read, to find keys nobody uses.
+//
+// Keys are read through Get or reported by generated accessors with Read. Pass Option to Load
+// to record the keys set by the sources.
+type AccessLog struct {
+	mu     sync.Mutex
+	loaded []string
+	read   map[string]struct{}
+}
+
+// NewAccessLog creates an empty AccessLog.
+func NewAccessLog() *AccessLog {
+	return &AccessLog{
+		mu:     sync.Mutex{},
+		loaded: nil,
+		read:   map[string]struct{}{},
+	}
+}
+
+// Option returns a config option that records the keys of every successful Load.
+func (l *AccessLog) Option() Option {
+	return OnLoad(func(info LoadInfo) {
+		if info.Err != nil {
+			return
+		}
+
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.loaded = info.Keys
+	})
+}
+
+// Read marks the key, e.g. "database.host", as read. Reading a section marks all of its keys.
+func (l *AccessLog) Read(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.read[key] = struct{}{}
+}
+
+// Get returns the value of the key in the configuration struct c and marks it as read.
+// Sections are returned as maps. It converts the whole struct, so avoid it on hot paths.
+func (l *AccessLog) Get(c any, key string, opts ...MarshalOption) (any, bool) {
+	l.Read(key)
+
+	m, err := toMap(c, false, newMarshalOptions(opts).tags)
+	if err != nil {
+		return nil, false
+	}
+
+	v := kmaps.Search(m, strings.Split(key, defaultKeyDelimiter))
+	return v, v != nil
+}
+
+// Unread returns the keys set by the last Load that were never read, sorted.
+func (l *AccessLog) Unread() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	unread := []string{}
+	for _, key := range l.loaded {
+		if !l.wasRead(key) {
+			unread = append(unread, key)
+		}
+	}
+	slices.Sort(unread)
+
+	return unread
+}
+
+func (l *AccessLog) wasRead(key string) bool {
+	for {
+		if _, ok := l.read[key]; ok {
+			return true
+		}
+
+		i := strings.LastIndex(key, defaultKeyDelimiter)
+		if i < 0 {
+			return false
+		}
+		key = key[:i]
+	}
+}
@@ -0,0 +1,36 @@
+package config_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAccessLog tests reporting keys that were loaded but never read
+func TestAccessLog(t *testing.T) {
+	files := fstest.MapFS{"config.yaml": {Data: []byte(`
+database:
+  host: db
+  port: 5432
+server:
+  port: 80
+legacy_timeout: 5s
+`)}}
+
+	log := config.NewAccessLog()
+	var c TestConfig
+	require.NoError(t, loadYAML(t, &c, files, log.Option()))
+	assert.Equal(t, []string{"database.host", "database.port", "legacy_timeout", "server.port"}, log.Unread())
+
+	v, ok := log.Get(&c, "database.host")
+	require.True(t, ok)
+	assert.Equal(t, "db", v)
+	log.Read("server")
+	assert.Equal(t, []string{"database.port", "legacy_timeout"}, log.Unread())
+
+	_, ok = log.Get(&c, "database.missing")
+	assert.False(t, ok)
+}
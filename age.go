@@ -0,0 +1,69 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+)
+
+var ErrNoAgeIdentity = errors.New("no age identity")
+
+// ageDecrypter decrypts armored age ciphertexts with identities returned by read.
+func ageDecrypter(read func() ([]byte, error)) decrypter {
+	return decrypter{
+		name: "age",
+		match: func(value string) bool {
+			return strings.HasPrefix(strings.TrimSpace(value), armor.Header)
+		},
+		decrypt: func(_ context.Context, value string) (string, error) {
+			raw, err := read()
+			if err != nil {
+				return "", err
+			}
+
+			identities, err := age.ParseIdentities(bytes.NewReader(raw))
+			if err != nil {
+				return "", fmt.Errorf("parse identities: %w", err)
+			}
+
+			r, err := age.Decrypt(armor.NewReader(strings.NewReader(strings.TrimSpace(value))), identities...)
+			if err != nil {
+				return "", fmt.Errorf("decrypt: %w", err)
+			}
+
+			plain, err := io.ReadAll(r)
+			if err != nil {
+				return "", fmt.Errorf("read plaintext: %w", err)
+			}
+
+			return string(plain), nil
+		},
+	}
+}
+
+func readAgeIdentityFile(path string) func() ([]byte, error) {
+	return func() ([]byte, error) {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read identity file: %w", err)
+		}
+		return b, nil
+	}
+}
+
+func readAgeIdentityEnv(name string, lookupEnv func(string) (string, bool)) func() ([]byte, error) {
+	return func() ([]byte, error) {
+		v, ok := lookupEnv(name)
+		if !ok || v == "" {
+			return nil, fmt.Errorf("%w: %s is not set", ErrNoAgeIdentity, name)
+		}
+		return []byte(v), nil
+	}
+}
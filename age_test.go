@@ -0,0 +1,89 @@
+package config_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// encryptAge encrypts the plaintext for the identity and returns the armored ciphertext
+func encryptAge(t *testing.T, identity *age.X25519Identity, plaintext string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	a := armor.NewWriter(&buf)
+	w, err := age.Encrypt(a, identity.Recipient())
+	require.NoError(t, err)
+	_, err = w.Write([]byte(plaintext))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.NoError(t, a.Close())
+	return buf.String()
+}
+
+// indent indents every line of s by the given prefix
+func indent(s, prefix string) string {
+	return prefix + strings.ReplaceAll(strings.TrimSpace(s), "\n", "\n"+prefix)
+}
+
+// TestAgeDecryption tests decryption of age-encrypted values
+func TestAgeDecryption(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	identityFile := writeTempFile(t, tmpDir, "key.txt", identity.String())
+	yamlFile := writeTempFile(t, tmpDir, "config.yaml", `database:
+  host: yaml-host
+  password: |
+`+indent(encryptAge(t, identity, "s3cr3t"), "    "))
+
+	t.Run("file", func(t *testing.T) {
+		var cfg TestConfig
+		err := config.Load(&cfg, config.WithLocalYAML(yamlFile), config.WithAgeIdentityFile(identityFile))
+		require.NoError(t, err)
+		assert.Equal(t, "yaml-host", cfg.Database.Host)
+		assert.Equal(t, "s3cr3t", cfg.Database.Password)
+	})
+
+	t.Run("env", func(t *testing.T) {
+		t.Setenv("TEST_AGE_IDENTITY", identity.String())
+		var cfg TestConfig
+		err := config.Load(&cfg, config.WithLocalYAML(yamlFile), config.WithAgeIdentityEnv("TEST_AGE_IDENTITY"))
+		require.NoError(t, err)
+		assert.Equal(t, "s3cr3t", cfg.Database.Password)
+	})
+
+	t.Run("virtual env", func(t *testing.T) {
+		var cfg TestConfig
+		err := config.Load(&cfg,
+			config.WithLocalYAML(yamlFile),
+			config.WithEnviron(map[string]string{"TEST_AGE_IDENTITY": identity.String()}),
+			config.WithAgeIdentityEnv("TEST_AGE_IDENTITY"),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, "s3cr3t", cfg.Database.Password)
+	})
+
+	t.Run("missing identity", func(t *testing.T) {
+		var cfg TestConfig
+		err := config.Load(&cfg, config.WithLocalYAML(yamlFile), config.WithAgeIdentityEnv("TEST_AGE_MISSING"))
+		require.ErrorIs(t, err, config.ErrNoAgeIdentity)
+	})
+
+	t.Run("wrong identity", func(t *testing.T) {
+		other, err := age.GenerateX25519Identity()
+		require.NoError(t, err)
+		otherFile := writeTempFile(t, tmpDir, "other.txt", other.String())
+
+		var cfg TestConfig
+		err = config.Load(&cfg, config.WithLocalYAML(yamlFile), config.WithAgeIdentityFile(otherFile))
+		require.ErrorContains(t, err, "decrypt database.password")
+	})
+}
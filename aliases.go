@@ -0,0 +1,45 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/knadh/koanf/v2"
+)
+
+// applyAliases moves values set under legacy keys to their new keys.
+//
+// Legacy sections are merged into the new ones leaf by leaf: if a source sets both the legacy
+// and the new key of the same leaf, the new key wins.
+func applyAliases(k *koanf.Koanf, aliases map[string]string) error {
+	for _, from := range slices.Sorted(maps.Keys(aliases)) {
+		if !k.Exists(from) {
+			continue
+		}
+
+		to := aliases[from]
+		v := k.Get(from)
+		leaves := map[string]any{from: v}
+		if _, ok := v.(map[string]any); ok {
+			leaves = map[string]any{}
+			for key, leaf := range k.Cut(from).All() {
+				leaves[from+k.Delim()+key] = leaf
+			}
+		}
+		k.Delete(from)
+
+		for _, key := range slices.Sorted(maps.Keys(leaves)) {
+			target := to + key[len(from):]
+			if k.Exists(target) {
+				continue
+			}
+
+			if err := k.Set(target, leaves[key]); err != nil {
+				return fmt.Errorf("alias %s to %s: %w", from, to, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWithAliases tests mapping of legacy keys to new ones
+func TestWithAliases(t *testing.T) {
+	t.Setenv("DB__HOST", "legacy-env-host")
+	t.Setenv("HTTP__PORT", "8080")
+
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	yamlFile := writeTempFile(t, tmpDir, "config.yaml", `db:
+  port: 3306
+  username: legacy-user
+database:
+  username: new-user`)
+
+	var p config.Provenance
+	var cfg TestConfig
+	err := config.Load(&cfg,
+		config.WithLocalYAML(yamlFile),
+		config.WithAliases(map[string]string{
+			"db.host":     "database.host",
+			"db.port":     "database.port",
+			"db.username": "database.username",
+			"http":        "server",
+		}),
+		config.WithProvenance(&p),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, "legacy-env-host", cfg.Database.Host)
+	assert.Equal(t, 3306, cfg.Database.Port)
+	assert.Equal(t, "new-user", cfg.Database.Username)
+	assert.Equal(t, 8080, cfg.Server.Port)
+
+	host, _ := p.Lookup("database.host")
+	assert.Equal(t, config.SourceEnv, host.Source)
+	_, ok := p.Lookup("db.host")
+	assert.False(t, ok)
+}
+
+// TestWithAliasesMerge tests merging of a legacy section into a new one set by the same source
+func TestWithAliasesMerge(t *testing.T) {
+	type Config struct {
+		Server struct {
+			Host string `koanf:"host"`
+			Port int    `koanf:"port"`
+		} `koanf:"server"`
+	}
+
+	var cfg Config
+	err := config.Load(&cfg,
+		config.WithEnviron(map[string]string{"HTTP__PORT": "8080", "HTTP__HOST": "legacy", "SERVER__HOST": "x"}),
+		config.WithAliases(map[string]string{"http": "server"}),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, "x", cfg.Server.Host)
+	assert.Equal(t, 8080, cfg.Server.Port)
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const appConfigFile = "config.yaml"
+
+// yamlPath returns the path of the local YAML file: the one set with WithLocalYAML or,
+// with WithAppName, the first existing file of the platform search paths.
+func (o *options) yamlPath() string {
+	if o.withYaml != "" || o.appName == "" {
+		return o.withYaml
+	}
+
+	for _, path := range o.appConfigPaths() {
+		if o.fileExists(path) {
+			return path
+		}
+	}
+
+	return ""
+}
+
+// appConfigPaths returns the search paths of the application config file, in order:
+// $XDG_CONFIG_HOME/app, ~/.config/app and /etc/app.
+func (o *options) appConfigPaths() []string {
+	var dirs []string
+	if xdg, ok := o.lookupEnv("XDG_CONFIG_HOME"); ok && filepath.IsAbs(xdg) {
+		dirs = append(dirs, xdg)
+	}
+	if home := o.homeDir(); home != "" {
+		dirs = append(dirs, filepath.Join(home, ".config"))
+	}
+	dirs = append(dirs, "/etc")
+
+	var paths []string
+	seen := map[string]bool{}
+	for _, dir := range dirs {
+		path := filepath.Join(dir, o.appName, appConfigFile)
+		if !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+
+	return paths
+}
+
+// homeDir returns $HOME from the environment set with WithEnviron or the home directory of the current user.
+func (o *options) homeDir() string {
+	if home, ok := o.lookupEnv("HOME"); ok || o.environ != nil {
+		return home
+	}
+
+	home, _ := os.UserHomeDir()
+	return home
+}
+
+func (o *options) fileExists(path string) bool {
+	var err error
+	if o.fsys != nil {
+		_, err = fs.Stat(o.fsys, path)
+	} else {
+		_, err = os.Stat(path)
+	}
+
+	return err == nil
+}
+
+// expandPaths expands `~` and environment variables in the paths set by options.
+func (o *options) expandPaths() {
+	o.withYaml = o.expandPath(o.withYaml)
+	o.bundle = o.expandPath(o.bundle)
+	o.cacheDir = o.expandPath(o.cacheDir)
+}
+
+// executableDir returns the directory of the running binary, with symlinks resolved.
+func executableDir() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("find executable: %w", err)
+	}
+
+	exe, err = filepath.EvalSymlinks(exe)
+	if err != nil {
+		return "", fmt.Errorf("find executable: %w", err)
+	}
+
+	return filepath.Dir(exe), nil
+}
+
+// expandPath replaces a leading `~` by the home directory and $VAR or ${VAR} by the value of the variable,
+// from the environment set with WithEnviron or the process environment. With WithExecutableRelativePaths,
+// relative paths are made relative to the directory of the binary.
+func (o *options) expandPath(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home := o.homeDir(); home != "" {
+			path = home + path[1:]
+		}
+	}
+
+	path = os.Expand(path, func(name string) string {
+		v, _ := o.lookupEnv(name)
+		return v
+	})
+
+	if o.exeDir != "" && path != "" && !filepath.IsAbs(path) {
+		path = filepath.Join(o.exeDir, path)
+	}
+
+	return path
+}
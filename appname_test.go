@@ -0,0 +1,75 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWithAppName tests searching the XDG config home before ~/.config
+func TestWithAppName(t *testing.T) {
+	t.Chdir(t.TempDir())
+	home := t.TempDir()
+	xdg := t.TempDir()
+	environ := map[string]string{"HOME": home, "XDG_CONFIG_HOME": xdg}
+
+	userFile := filepath.Join(home, ".config", "myapp", "config.yaml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(userFile), 0o755))
+	require.NoError(t, os.WriteFile(userFile, []byte("database:\n  host: home\n"), 0o644))
+
+	var c TestConfig
+	var p config.Provenance
+	require.NoError(t, config.Load(&c, config.WithAppName("myapp"), config.WithEnviron(environ), config.WithProvenance(&p)))
+	assert.Equal(t, "home", c.Database.Host)
+	assert.Equal(t, userFile, p["database.host"].Location)
+
+	xdgFile := filepath.Join(xdg, "myapp", "config.yaml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(xdgFile), 0o755))
+	require.NoError(t, os.WriteFile(xdgFile, []byte("database:\n  host: xdg\n"), 0o644))
+
+	require.NoError(t, config.Load(&c, config.WithAppName("myapp"), config.WithEnviron(environ)))
+	assert.Equal(t, "xdg", c.Database.Host)
+
+	// an explicit path takes precedence
+	require.NoError(t, config.Load(&c, config.WithAppName("myapp"), config.WithLocalYAML(userFile), config.WithEnviron(environ)))
+	assert.Equal(t, "home", c.Database.Host)
+}
+
+// TestPathExpansion tests expanding ~ and environment variables in option paths
+func TestPathExpansion(t *testing.T) {
+	t.Chdir(t.TempDir())
+	home := t.TempDir()
+	writeTempFile(t, home, "config.yaml", "database:\n  host: home\n")
+	environ := config.WithEnviron(map[string]string{"HOME": home, "CONFIG_DIR": home})
+
+	for _, path := range []string{"~/config.yaml", "$CONFIG_DIR/config.yaml", "${HOME}/config.yaml"} {
+		var c TestConfig
+		require.NoError(t, config.Load(&c, config.WithLocalYAML(path), environ))
+		assert.Equal(t, "home", c.Database.Host, path)
+	}
+}
+
+// TestWithExecutableRelativePaths tests resolving relative paths against the directory of the binary
+func TestWithExecutableRelativePaths(t *testing.T) {
+	t.Chdir(t.TempDir())
+	exe, err := os.Executable()
+	require.NoError(t, err)
+	exe, err = filepath.EvalSymlinks(exe)
+	require.NoError(t, err)
+
+	var locations []string
+	onLoaded := config.OnSourceLoaded(func(s config.SourceInfo) {
+		if s.Name == config.SourceYAML {
+			locations = append(locations, s.Location)
+		}
+	})
+
+	var c TestConfig
+	require.NoError(t, config.Load(&c, config.WithLocalYAML("config.yaml"), config.WithExecutableRelativePaths(), onLoaded))
+	require.NoError(t, config.Load(&c, config.WithLocalYAML("/etc/app.yaml"), config.WithExecutableRelativePaths(), onLoaded))
+	assert.Equal(t, []string{filepath.Join(filepath.Dir(exe), "config.yaml"), "/etc/app.yaml"}, locations)
+}
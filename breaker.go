@@ -0,0 +1,141 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrCircuitOpen = errors.New("circuit open")
+
+// BreakerState is the state of the circuit of a single source.
+type BreakerState string
+
+const (
+	// BreakerClosed means the source is loaded normally.
+	BreakerClosed BreakerState = "closed"
+	// BreakerOpen means the source is skipped until the next probe.
+	BreakerOpen BreakerState = "open"
+	// BreakerHalfOpen means the next load of the source is a probe.
+	BreakerHalfOpen BreakerState = "half-open"
+)
+
+// CircuitBreaker stops loading custom sources that keep failing, e.g. during Watcher.Poll.
+//
+// After threshold consecutive failures the circuit of a source opens: loads fail fast with
+// ErrCircuitOpen, falling back to the source cache if configured. Once the backoff delay has
+// passed, the next load probes the source again; success closes the circuit, failure reopens it
+// with the next backoff delay. A CircuitBreaker is shared by all loads it is passed to.
+type CircuitBreaker struct {
+	threshold int
+	backoff   Backoff
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+type circuit struct {
+	failures int
+	opened   int
+	until    time.Time
+}
+
+// NewCircuitBreaker creates a CircuitBreaker opening after threshold consecutive failures
+// and probing with the delays of the given backoff. Once the backoff gives up, its last delay is kept.
+func NewCircuitBreaker(threshold int, backoff Backoff) *CircuitBreaker {
+	return &CircuitBreaker{
+		threshold: max(threshold, 1),
+		backoff:   backoff,
+		mu:        sync.Mutex{},
+		circuits:  map[string]*circuit{},
+	}
+}
+
+// State returns the state of the circuit of the named source.
+func (b *CircuitBreaker) State(source string) BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state(b.circuits[source])
+}
+
+// States returns the states of the circuits of all sources seen so far.
+func (b *CircuitBreaker) States() map[string]BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	states := make(map[string]BreakerState, len(b.circuits))
+	for name, c := range b.circuits {
+		states[name] = b.state(c)
+	}
+
+	return states
+}
+
+// Check returns an error wrapping ErrCircuitOpen if the circuit of any source is not closed,
+// for use as a health check.
+func (b *CircuitBreaker) Check() error {
+	var open []string
+	for name, state := range b.States() {
+		if state != BreakerClosed {
+			open = append(open, name)
+		}
+	}
+	if len(open) == 0 {
+		return nil
+	}
+
+	slices.Sort(open)
+	return fmt.Errorf("%w: %s", ErrCircuitOpen, strings.Join(open, ", "))
+}
+
+func (b *CircuitBreaker) state(c *circuit) BreakerState {
+	switch {
+	case c == nil || c.opened == 0:
+		return BreakerClosed
+	case time.Now().Before(c.until):
+		return BreakerOpen
+	default:
+		return BreakerHalfOpen
+	}
+}
+
+// allow reports whether the named source may be loaded now.
+func (b *CircuitBreaker) allow(source string) bool {
+	return b.State(source) != BreakerOpen
+}
+
+// record updates the circuit of the named source with the result of a load.
+func (b *CircuitBreaker) record(source string, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c, ok := b.circuits[source]
+	if !ok {
+		c = new(circuit)
+		b.circuits[source] = c
+	}
+
+	if err == nil {
+		*c = circuit{failures: 0, opened: 0, until: time.Time{}}
+		return
+	}
+
+	c.failures++
+	if c.opened == 0 && c.failures < b.threshold {
+		return
+	}
+
+	c.opened++
+	delay := time.Duration(0)
+	for attempt := c.opened; attempt > 0 && b.backoff != nil; attempt-- {
+		if d, ok := b.backoff(attempt); ok {
+			delay = d
+			break
+		}
+	}
+	c.until = time.Now().Add(delay)
+}
@@ -0,0 +1,47 @@
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCircuitBreaker tests that a failing source is skipped until the next probe
+func TestCircuitBreaker(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	delay := 50 * time.Millisecond
+	b := config.NewCircuitBreaker(2, config.ConstantBackoff(delay, 10))
+	src := &fakeSource{data: map[string]any{"server": map[string]any{"port": 8080}}, failures: 3}
+	load := func() error {
+		var cfg TestConfig
+		return config.Load(&cfg, config.WithSource(src), config.WithCircuitBreaker(b))
+	}
+
+	require.ErrorIs(t, load(), errUnavailable)
+	assert.Equal(t, config.BreakerClosed, b.State("remote"))
+	require.NoError(t, b.Check())
+
+	require.ErrorIs(t, load(), errUnavailable)
+	assert.Equal(t, config.BreakerOpen, b.State("remote"))
+	require.ErrorIs(t, b.Check(), config.ErrCircuitOpen)
+
+	// open: the source is not called
+	require.ErrorIs(t, load(), config.ErrCircuitOpen)
+	assert.Equal(t, 2, src.attempts)
+
+	// the probe fails and reopens the circuit
+	time.Sleep(delay)
+	assert.Equal(t, config.BreakerHalfOpen, b.State("remote"))
+	require.ErrorIs(t, load(), errUnavailable)
+	assert.Equal(t, config.BreakerOpen, b.State("remote"))
+
+	// the next probe succeeds and closes it
+	time.Sleep(delay)
+	require.NoError(t, load())
+	assert.Equal(t, 4, src.attempts)
+	assert.Equal(t, map[string]config.BreakerState{"remote": config.BreakerClosed}, b.States())
+}
@@ -0,0 +1,148 @@
+package config
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"slices"
+
+	"github.com/knadh/koanf/v2"
+)
+
+var ErrInvalidBundle = errors.New("invalid bundle")
+
+// bundle holds the regular files of a tar or zip archive by their cleaned paths.
+type bundle map[string][]byte
+
+// loadBundle loads the YAML files of the archive at name in lexical order of their paths,
+// so later files override earlier ones, e.g. conf.d/00-base.yaml before conf.d/10-prod.yaml.
+func loadBundle(ctx context.Context, name string, k *koanf.Koanf, options *options) (int, error) {
+	if name == "" {
+		return 0, nil
+	}
+
+	data, err := options.fileProvider(name).ReadBytes()
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("load bundle: %w", err)
+	}
+
+	b, err := readBundle(data)
+	if err != nil {
+		return len(data), fmt.Errorf("load bundle %s: %w", name, err)
+	}
+
+	for _, file := range slices.Sorted(maps.Keys(b)) {
+		if ext := path.Ext(file); ext != ".yaml" && ext != ".yml" {
+			continue
+		}
+
+		// includes and !file references are resolved within the bundle
+		parser := options.yamlParser(ctx, file)
+		parser.read = b.read
+
+		m, err := parser.Unmarshal(b[file])
+		if err != nil {
+			return len(data), fmt.Errorf("load bundle %s: %s: %w", name, file, err)
+		}
+		if err := k.Load(rawProvider(m), nil); err != nil {
+			return len(data), fmt.Errorf("load bundle %s: %s: %w", name, file, err)
+		}
+	}
+
+	return len(data), nil
+}
+
+func (b bundle) read(name string) ([]byte, error) {
+	data, ok := b[path.Clean(name)]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return data, nil
+}
+
+// readBundle reads a zip archive or a tar archive, compressed archives are already decompressed
+// by the file provider.
+func readBundle(data []byte) (bundle, error) {
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		return readZip(data)
+	}
+
+	return readTar(data)
+}
+
+func readZip(data []byte) (bundle, error) {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBundle, err)
+	}
+
+	b := bundle{}
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidBundle, f.Name, err)
+		}
+		data, err := readEntry(rc)
+		_ = rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidBundle, f.Name, err)
+		}
+
+		b[path.Clean(f.Name)] = data
+	}
+
+	return b, nil
+}
+
+func readTar(data []byte) (bundle, error) {
+	r := tar.NewReader(bytes.NewReader(data))
+
+	b := bundle{}
+	for {
+		h, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			return b, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidBundle, err)
+		}
+		if h.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		data, err := readEntry(r)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidBundle, h.Name, err)
+		}
+
+		b[path.Clean(h.Name)] = data
+	}
+}
+
+func readEntry(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxDecompressedSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read: %w", err)
+	}
+	if len(data) > maxDecompressedSize {
+		return nil, fmt.Errorf("%w: more than %d bytes", ErrTooLarge, maxDecompressedSize)
+	}
+
+	return data, nil
+}
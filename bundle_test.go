@@ -0,0 +1,103 @@
+package config_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var bundleFiles = map[string]string{
+	"conf.d/10-prod.yaml": "database:\n  host: prod\n  password: !file ../secrets/db\n",
+	"conf.d/00-base.yaml": "database:\n  host: base\n  port: 5432\nserver: !include server.yaml\n",
+	"conf.d/server.yaml":  "port: 8080\n",
+	"secrets/db":          "s3cr3t\n",
+	"README.md":           "not: loaded\n",
+}
+
+func tarBundle(t *testing.T, compress bool) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	var out io.Writer = &buf
+	if compress {
+		out = gz
+	}
+
+	w := tar.NewWriter(out)
+	for name, data := range bundleFiles {
+		require.NoError(t, w.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(data)), Typeflag: tar.TypeReg}))
+		_, err := w.Write([]byte(data))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	if compress {
+		require.NoError(t, gz.Close())
+	}
+
+	return buf.Bytes()
+}
+
+func zipBundle(t *testing.T) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, data := range bundleFiles {
+		f, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(data))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+
+	return buf.Bytes()
+}
+
+// TestBundle tests loading the YAML files of tar and zip archives in lexical order
+func TestBundle(t *testing.T) {
+	bundles := map[string][]byte{
+		"config.tar":    tarBundle(t, false),
+		"config.tar.gz": tarBundle(t, true),
+		"config.zip":    zipBundle(t),
+	}
+
+	for name, data := range bundles {
+		t.Run(name, func(t *testing.T) {
+			var c TestConfig
+			var p config.Provenance
+			require.NoError(t, config.Load(&c,
+				config.WithFS(fstest.MapFS{name: {Data: data}}),
+				config.WithBundle(name),
+				config.WithEnviron(nil),
+				config.WithProvenance(&p),
+			))
+			assert.Equal(t, "prod", c.Database.Host)
+			assert.Equal(t, 5432, c.Database.Port)
+			assert.Equal(t, "s3cr3t", c.Database.Password)
+			assert.Equal(t, 8080, c.Server.Port)
+			assert.Equal(t, config.SourceBundle, p["database.host"].Source)
+		})
+	}
+}
+
+// TestBundleErrors tests missing and invalid archives
+func TestBundleErrors(t *testing.T) {
+	var c TestConfig
+	require.NoError(t, config.Load(&c, config.WithFS(fstest.MapFS{}), config.WithBundle("missing.tar"), config.WithEnviron(nil)))
+
+	err := config.Load(&c,
+		config.WithFS(fstest.MapFS{"config.tar": {Data: []byte("not an archive")}}),
+		config.WithBundle("config.tar"),
+		config.WithEnviron(nil),
+	)
+	require.ErrorIs(t, err, config.ErrInvalidBundle)
+}
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"reflect"
+)
+
+// deepCopy returns a copy of v sharing no pointers, maps or slices reachable through exported fields.
+// Unexported fields are copied shallowly.
+func deepCopy(v reflect.Value, depth int) reflect.Value {
+	if !v.IsValid() || depth > maxFieldDepth {
+		return v
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		p := reflect.New(v.Type().Elem())
+		p.Elem().Set(deepCopy(v.Elem(), depth+1))
+		return p
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		i := reflect.New(v.Type()).Elem()
+		i.Set(deepCopy(v.Elem(), depth+1))
+		return i
+	case reflect.Struct:
+		s := reflect.New(v.Type()).Elem()
+		s.Set(v)
+		for i := range v.NumField() {
+			if v.Type().Field(i).IsExported() {
+				s.Field(i).Set(deepCopy(v.Field(i), depth+1))
+			}
+		}
+		return s
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		s := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := range v.Len() {
+			s.Index(i).Set(deepCopy(v.Index(i), depth+1))
+		}
+		return s
+	case reflect.Array:
+		a := reflect.New(v.Type()).Elem()
+		for i := range v.Len() {
+			a.Index(i).Set(deepCopy(v.Index(i), depth+1))
+		}
+		return a
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		m := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			m.SetMapIndex(iter.Key(), deepCopy(iter.Value(), depth+1))
+		}
+		return m
+	default:
+		return v
+	}
+}
+
+// Clone returns a deep copy of the configuration c: a change to the copy, including its maps, slices
+// and pointed-to values, does not affect c. Unexported fields, e.g. of custom types, are copied shallowly.
+func Clone[T any](c T) T {
+	v := reflect.ValueOf(&c).Elem()
+	return deepCopy(v, 0).Interface().(T) //nolint:forcetypeassert // the copy has the type of c
+}
+
+// Equal reports whether two configurations have the same effective values.
+//
+// Unlike reflect.DeepEqual, it compares custom types such as Duration, URL or Secret by their values,
+// and treats nil and empty lists and maps as equal. Entries of OrderedMap fields must be in the same order.
+// Values that are not structs or maps are compared with reflect.DeepEqual.
+func Equal[T any](a, b T) bool {
+	am, errA := toOrderedMap(a, false, []string{tagName})
+	bm, errB := toOrderedMap(b, false, []string{tagName})
+	if errA != nil || errB != nil {
+		return reflect.DeepEqual(a, b)
+	}
+
+	ao, bo := map[string][]string{}, map[string][]string{}
+	keyOrders(ao, "", defaultKeyDelimiter, am)
+	keyOrders(bo, "", defaultKeyDelimiter, bm)
+
+	return reflect.DeepEqual(compact(unordered(am)), compact(unordered(bm))) && reflect.DeepEqual(ao, bo)
+}
+
+// compact drops nil values and empty lists and maps from maps, recursively.
+func compact(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(v))
+		for key, val := range v {
+			val = compact(val)
+			if isEmpty(val) {
+				continue
+			}
+			m[key] = val
+		}
+		return m
+	case []any:
+		s := make([]any, len(v))
+		for i, val := range v {
+			s[i] = compact(val)
+		}
+		return s
+	default:
+		return v
+	}
+}
+
+func isEmpty(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case map[string]any:
+		return len(v) == 0
+	case []any:
+		return len(v) == 0
+	default:
+		return false
+	}
+}
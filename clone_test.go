@@ -0,0 +1,60 @@
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type cloneConfig struct {
+	Timeout  time.Duration     `koanf:"timeout"`
+	Password config.Secret     `koanf:"password"`
+	Endpoint config.URL        `koanf:"endpoint"`
+	Hosts    []string          `koanf:"hosts"`
+	Labels   map[string]string `koanf:"labels"`
+	Limit    *int              `koanf:"limit"`
+}
+
+// TestClone tests that a clone shares no maps, slices or pointers with the original
+func TestClone(t *testing.T) {
+	limit := 10
+	c := cloneConfig{
+		Timeout:  time.Second,
+		Password: "s3cret",
+		Hosts:    []string{"a"},
+		Labels:   map[string]string{"env": "prod"},
+		Limit:    &limit,
+	}
+
+	cp := config.Clone(c)
+	cp.Hosts[0] = "b"
+	cp.Labels["env"] = "dev"
+	*cp.Limit = 20
+
+	assert.Equal(t, []string{"a"}, c.Hosts)
+	assert.Equal(t, "prod", c.Labels["env"])
+	assert.Equal(t, 10, limit)
+	assert.Equal(t, time.Second, cp.Timeout)
+	assert.Equal(t, config.Secret("s3cret"), cp.Password)
+}
+
+// TestEqual tests comparing configurations by their effective values
+func TestEqual(t *testing.T) {
+	u1, err := config.ParseURL("https://example.com/api")
+	require.NoError(t, err)
+	u2, err := config.ParseURL("https://example.com/api")
+	require.NoError(t, err)
+
+	a := cloneConfig{Timeout: time.Minute, Password: "a", Endpoint: u1, Hosts: nil, Labels: nil, Limit: nil}
+	b := cloneConfig{Timeout: time.Minute, Password: "a", Endpoint: u2, Hosts: []string{}, Labels: map[string]string{}, Limit: nil}
+	assert.True(t, config.Equal(a, b))
+	assert.True(t, config.Equal(&a, &b))
+
+	b.Password = "b"
+	assert.False(t, config.Equal(a, b))
+
+	assert.True(t, config.Equal(1, 1))
+}
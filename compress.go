@@ -0,0 +1,78 @@
+package config
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/knadh/koanf/v2"
+)
+
+// maxDecompressedSize limits the size of decompressed configuration files.
+const maxDecompressedSize = 64 << 20
+
+var ErrTooLarge = errors.New("decompressed file too large")
+
+// decompressor returns a reader decompressing r.
+type decompressor func(r io.Reader) (io.ReadCloser, error)
+
+//nolint:gochecknoglobals // read-only lookup table
+var decompressors = map[string]decompressor{
+	".gz": func(r io.Reader) (io.ReadCloser, error) {
+		return gzip.NewReader(r) //nolint:wrapcheck // wrapped by the caller
+	},
+	".tgz": func(r io.Reader) (io.ReadCloser, error) {
+		return gzip.NewReader(r) //nolint:wrapcheck // wrapped by the caller
+	},
+	".zst": func(r io.Reader) (io.ReadCloser, error) {
+		d, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err //nolint:wrapcheck // wrapped by the caller
+		}
+		return d.IOReadCloser(), nil
+	},
+}
+
+// decompressing wraps p to decompress files compressed with gzip (.gz, .tgz) or zstd (.zst),
+// based on the extension of path.
+func decompressing(p koanf.Provider, path string) koanf.Provider {
+	d, ok := decompressors[filepath.Ext(path)]
+	if !ok {
+		return p
+	}
+
+	return decompressingProvider{Provider: p, decompress: d}
+}
+
+type decompressingProvider struct {
+	koanf.Provider
+
+	decompress decompressor
+}
+
+func (p decompressingProvider) ReadBytes() ([]byte, error) {
+	b, err := p.Provider.ReadBytes()
+	if err != nil {
+		return nil, err //nolint:wrapcheck // passed through to koanf
+	}
+
+	r, err := p.decompress(bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("decompress: %w", err)
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(io.LimitReader(r, maxDecompressedSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("decompress: %w", err)
+	}
+	if len(data) > maxDecompressedSize {
+		return nil, fmt.Errorf("%w: more than %d bytes", ErrTooLarge, maxDecompressedSize)
+	}
+
+	return data, nil
+}
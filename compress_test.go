@@ -0,0 +1,48 @@
+package config_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-core-fx/config"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCompressedYAML tests decompressing gzip and zstd files based on the extension
+func TestCompressedYAML(t *testing.T) {
+	data := []byte("database:\n  host: compressed\n")
+
+	var gz bytes.Buffer
+	w := gzip.NewWriter(&gz)
+	_, err := w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	enc, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+	zst := enc.EncodeAll(data, nil)
+
+	for path, data := range map[string][]byte{"config.yaml.gz": gz.Bytes(), "config.yaml.zst": zst} {
+		t.Run(path, func(t *testing.T) {
+			var c TestConfig
+			require.NoError(t, config.Load(&c,
+				config.WithFS(fstest.MapFS{path: {Data: data}}),
+				config.WithLocalYAML(path),
+				config.WithEnviron(nil),
+			))
+			assert.Equal(t, "compressed", c.Database.Host)
+		})
+	}
+
+	var c TestConfig
+	err = config.Load(&c,
+		config.WithFS(fstest.MapFS{"config.yaml.gz": {Data: data}}),
+		config.WithLocalYAML("config.yaml.gz"),
+		config.WithEnviron(nil),
+	)
+	require.ErrorIs(t, err, gzip.ErrHeader)
+}
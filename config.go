@@ -1,83 +1,524 @@
 package config
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
+	"maps"
 	"os"
+	"reflect"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/go-core-fx/config/internal/overrides"
 	"github.com/knadh/koanf/parsers/dotenv"
-	"github.com/knadh/koanf/parsers/yaml"
 	"github.com/knadh/koanf/providers/env/v2"
-	"github.com/knadh/koanf/providers/file"
 	"github.com/knadh/koanf/v2"
 )
 
+const (
+	dotenvPath          = ".env"
+	defaultKeyDelimiter = "."
+	envDelimiter        = "__"
+)
+
+// layer is a single configuration source merged into the effective configuration.
+type layer struct {
+	name     string
+	location string
+	// foldCase matches keys of earlier sources that differ only in case, for environment-style sources.
+	foldCase bool
+	// vars records the environment variable setting each key loaded, reported as Origin.Location.
+	vars map[string]string
+	// load loads the source into k and returns the size of the raw data read, if known.
+	load func(k *koanf.Koanf) (int, error)
+}
+
 // Load reads configuration from various sources and unmarshals it into a given struct.
 //
 // It looks for configuration in the following order (later overrides earlier):
-// 1. Local file, if `WithLocalYAML` is provided.
-// 2. `.env` file in the current working directory.
-// 3. Environment variables.
+// 1. Local file, if `WithLocalYAML` or `WithAppName` is provided.
+// 2. YAML files of the archive, if `WithBundle` is provided.
+// 3. Koanf instances passed with `WithKoanf`, then custom sources added with `WithSource`.
+// 4. `.env` file in the current working directory.
+// 5. systemd credentials, if `WithSystemdCredentials` is provided.
+// 6. Environment variables.
+// 7. Command-line flags registered with `RegisterFlags`.
+// 8. Overrides set with `Watcher.Set` and, in tests, `configtest.Override`.
+//
+// With `WithTenant`, the tenant's section is then merged over the result.
 //
 // If any of the above sources result in an error (other than `os.ErrNotExist`), it will be returned.
 //
 // If a source results in `os.ErrNotExist`, it will be skipped.
 //
 // The final configuration will be unmarshaled into the given struct. If unmarshaling fails, an error will be returned.
+// Targets other than structs and maps with string keys are rejected with ErrUnsupportedTarget before any source is read.
 func Load[T any](c *T, opts ...Option) error {
-	options := new(options)
+	return LoadContext(context.Background(), c, opts...)
+}
+
+// LoadContext is like Load but passes the given context to sources and decryptors.
+func LoadContext[T any](ctx context.Context, c *T, opts ...Option) error {
+	return LoadAllContext(ctx, opts, Target("", c))
+}
+
+// LoadRaw is like Load but returns the effective configuration as nested maps, for tools that do not know
+// the configuration struct at compile time. Values keep the types produced by the sources,
+// e.g. strings for environment variables.
+func LoadRaw(opts ...Option) (map[string]any, error) {
+	m := map[string]any{}
+	if err := LoadContext(context.Background(), &m, opts...); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// LoadTarget is a struct populated by LoadAll, created with Target.
+type LoadTarget struct {
+	prefix string
+	c      any
+}
+
+// Target returns a LoadTarget unmarshaling the keys under prefix, e.g. "database", into the struct
+// pointed to by c. An empty prefix unmarshals the whole configuration.
+func Target(prefix string, c any) LoadTarget {
+	return LoadTarget{prefix: prefix, c: c}
+}
+
+// LoadAll is like Load but reads the sources once and unmarshals the configuration into every target.
+//
+// Each target is validated on its own. A schema set with WithSchema applies to targets without a prefix.
+func LoadAll(opts []Option, targets ...LoadTarget) error {
+	return LoadAllContext(context.Background(), opts, targets...)
+}
+
+// LoadAllContext is like LoadAll but passes the given context to sources and decryptors.
+func LoadAllContext(ctx context.Context, opts []Option, targets ...LoadTarget) error {
+	options := newOptions()
 	options.apply(opts...)
+	options.expandPaths()
 
-	k := koanf.New(".")
+	start := time.Now()
+	info := LoadInfo{Sources: nil, Keys: nil, Duration: 0, Deprecated: nil, Hash: "", Err: nil}
+	err := checkTargets(targets)
+	if err == nil {
+		for _, t := range targets {
+			if d, ok := t.c.(Defaulter); ok {
+				d.SetDefaults()
+			}
+		}
+		info, err = load(ctx, targets, options)
+	}
+	info.Duration = time.Since(start)
+	info.Err = err
 
-	if err := loadFromYAML(options.withYaml, k); err != nil {
-		return err
+	for _, fn := range options.onLoad {
+		fn(info)
+	}
+
+	return err
+}
+
+func load(ctx context.Context, targets []LoadTarget, options *options) (LoadInfo, error) {
+	info := LoadInfo{Sources: nil, Keys: nil, Duration: 0, Deprecated: nil, Hash: "", Err: nil}
+
+	k, provenance, sources, err := mergeSources(ctx, options)
+	info.Sources = sources
+	if err != nil {
+		return info, err
+	}
+
+	if err := postMerge(ctx, k, options); err != nil {
+		return info, err
+	}
+
+	info.Keys = k.Keys()
+	for _, t := range targets {
+		info.Deprecated = append(info.Deprecated, targetDeprecations(k, t, options)...)
+	}
+	options.warnDeprecated(info.Deprecated)
+
+	if options.provenance != nil {
+		*options.provenance = prune(provenance, k)
+	}
+
+	for _, t := range targets {
+		if err := decodeTarget(k, t, options); err != nil {
+			if t.prefix != "" {
+				return info, fmt.Errorf("%s: %w", t.prefix, err)
+			}
+			return info, err
+		}
+	}
+
+	loaded := loadedValue(targets)
+	if summary, err := toMap(loaded, true, options.tags); err == nil && !options.dryRun {
+		options.logger.Info("config loaded", slog.Any("config", summary))
+	}
+
+	info.Hash, _ = Hash(loaded, withTags(options.tags))
+
+	return info, nil
+}
+
+// loadedValue returns the value logged and hashed after a load: a single target as is, several by prefix.
+func loadedValue(targets []LoadTarget) any {
+	if len(targets) == 1 {
+		return targets[0].c
+	}
+
+	m := make(map[string]any, len(targets))
+	for _, t := range targets {
+		m[t.prefix] = t.c
+	}
+
+	return m
+}
+
+// checkTargets returns ErrUnsupportedTarget unless every target is a non-nil pointer to a struct
+// or to a map with string keys.
+func checkTargets(targets []LoadTarget) error {
+	for _, t := range targets {
+		v := reflect.ValueOf(t.c)
+		if v.Kind() != reflect.Pointer || v.IsNil() {
+			return fmt.Errorf("%w: %T, want a non-nil pointer to a struct or map", ErrUnsupportedTarget, t.c)
+		}
+
+		e := v.Type().Elem()
+		if e.Kind() != reflect.Struct && (e.Kind() != reflect.Map || e.Key().Kind() != reflect.String) {
+			return fmt.Errorf("%w: %T, want a pointer to a struct or map with string keys", ErrUnsupportedTarget, t.c)
+		}
+	}
+
+	return nil
+}
+
+// mergeSources loads all layers and merges them in order.
+func mergeSources(ctx context.Context, options *options) (*koanf.Koanf, Provenance, []SourceInfo, error) {
+	k := koanf.New(options.delim)
+	provenance := Provenance{}
+	var sources []SourceInfo
+
+	for _, l := range layers(ctx, options) {
+		lk := koanf.New(options.delim)
+		start := time.Now()
+		size, err := l.load(lk)
+		if err != nil {
+			options.sourceLoaded(SourceInfo{
+				Name: l.name, Location: l.location, Keys: nil, Bytes: size, Duration: time.Since(start), Err: err,
+			})
+			return nil, nil, sources, err
+		}
+
+		if lk, err = migrate(lk, options.migrations, options.delim); err != nil {
+			return nil, nil, sources, fmt.Errorf("%s: %w", l.name, err)
+		}
+
+		if err := applyAliases(lk, options.aliases); err != nil {
+			return nil, nil, sources, err
+		}
+		l.vars = aliasVars(l.vars, options.aliases, options.delim)
+		if l.foldCase {
+			if lk, err = matchKeyCase(k, lk, options.delim); err != nil {
+				return nil, nil, sources, fmt.Errorf("%s: %w", l.name, err)
+			}
+		}
+		options.dropLocked(k, lk, l.name)
+
+		src := SourceInfo{
+			Name: l.name, Location: l.location, Keys: lk.Keys(), Bytes: size, Duration: time.Since(start), Err: nil,
+		}
+		sources = append(sources, src)
+		options.sourceLoaded(src)
+
+		track(provenance, src.Keys, l, options.delim)
+
+		if err := mergeLayer(k, lk, options); err != nil {
+			return nil, nil, sources, fmt.Errorf("%s: %w", l.name, err)
+		}
+	}
+
+	return k, provenance, sources, nil
+}
+
+// targetDeprecations returns the deprecated keys of the target struct set in k, with the prefix of the target.
+func targetDeprecations(k *koanf.Koanf, t LoadTarget, options *options) []Deprecation {
+	if t.prefix == "" {
+		return deprecations(reflect.TypeOf(t.c), k, options.tags)
+	}
+
+	found := deprecations(reflect.TypeOf(t.c), k.Cut(t.prefix), options.tags)
+	for i := range found {
+		found[i].Key = t.prefix + options.delim + found[i].Key
 	}
 
-	if err := loadDotenv(k); err != nil {
+	return found
+}
+
+// decodeTarget unmarshals the keys of the target, derives fields and validates the result.
+func decodeTarget(k *koanf.Koanf, t LoadTarget, options *options) error {
+	validate := options.schema != nil && t.prefix == ""
+	if validate {
+		if err := validateConfig(options.schema, k.Raw(), t.c, options.tags); err != nil {
+			return err
+		}
+	}
+
+	if err := k.UnmarshalWithConf(t.prefix, t.c, unmarshalConf(options)); err != nil {
+		return fmt.Errorf("unmarshal: %w", err)
+	}
+	applyKeyOrder(reflect.ValueOf(t.c), t.prefix, options, 0)
+
+	var before map[string]any
+	if validate {
+		before, _ = toMap(t.c, false, options.tags)
+	}
+	if err := deriveFields(t.c, options.tags); err != nil {
 		return err
 	}
+	if validate {
+		// derived values are validated too, on top of the raw configuration
+		raw := k.Raw()
+		if after, err := toMap(t.c, false, options.tags); err == nil && overlayDerived(raw, before, after) {
+			if err := validateConfig(options.schema, raw, t.c, options.tags); err != nil {
+				return err
+			}
+		}
+	}
+
+	return validateEnums(t.c, options.tags)
+}
 
-	if err := loadEnv(k); err != nil {
+// postMerge applies the tenant overlay, decryption and transforms to the merged configuration.
+func postMerge(ctx context.Context, k *koanf.Koanf, options *options) error {
+	if options.tenant != "" {
+		if err := applyTenant(k, options.tenant, options); err != nil {
+			return err
+		}
+	}
+
+	if err := decryptValues(ctx, k, options.decrypters); err != nil {
 		return err
 	}
 
-	if err := k.Unmarshal("", c); err != nil {
-		return fmt.Errorf("unmarshal: %w", err)
+	for _, fn := range options.transforms {
+		if err := fn(k); err != nil {
+			return fmt.Errorf("transform: %w", err)
+		}
 	}
 
 	return nil
 }
 
-func loadFromYAML(path string, k *koanf.Koanf) error {
-	if path == "" {
-		return nil
+func layers(ctx context.Context, options *options) []layer {
+	path := options.yamlPath()
+	ls := []layer{
+		{
+			name:     SourceYAML,
+			location: path,
+			foldCase: false,
+			vars:     nil,
+			load: func(k *koanf.Koanf) (int, error) {
+				return loadFromYAML(path, k, options.fileProvider, options.yamlParser(ctx, path))
+			},
+		},
 	}
 
-	err := k.Load(file.Provider(path), yaml.Parser())
-	if err != nil && !errors.Is(err, os.ErrNotExist) {
-		return fmt.Errorf("load yaml: %w", err)
+	if options.bundle != "" {
+		ls = append(ls, layer{
+			name:     SourceBundle,
+			location: options.bundle,
+			foldCase: false,
+			vars:     nil,
+			load:     func(k *koanf.Koanf) (int, error) { return loadBundle(ctx, options.bundle, k, options) },
+		})
+	}
+
+	for _, kk := range options.koanfs {
+		ls = append(ls, layer{
+			name:     SourceKoanf,
+			location: "",
+			foldCase: false,
+			vars:     nil,
+			load:     func(k *koanf.Koanf) (int, error) { return 0, loadKoanf(k, kk) },
+		})
+	}
+
+	for _, src := range options.sources {
+		ls = append(ls, sourceLayer(ctx, src, options))
+	}
+
+	ls = append(ls, []layer{
+		{
+			name:     SourceDotenv,
+			location: dotenvPath,
+			foldCase: true,
+			vars:     nil,
+			load: func(k *koanf.Koanf) (int, error) {
+				return loadDotenv(k, envTransform(options.mapKey), options.fileProvider)
+			},
+		},
+	}...)
+
+	if options.credentials {
+		dir := options.credentialsDir()
+		ls = append(ls, layer{
+			name:     SourceCredentials,
+			location: dir,
+			foldCase: true,
+			vars:     nil,
+			load:     func(k *koanf.Koanf) (int, error) { return loadCredentials(k, dir, options) },
+		})
+	}
+
+	vars := map[string]string{}
+	ls = append(ls, layer{
+		name:     SourceEnv,
+		location: "",
+		foldCase: true,
+		vars:     vars,
+		load: func(k *koanf.Koanf) (int, error) {
+			return 0, loadEnv(k, recordVars(envTransform(options.mapKey), vars, options.delim), options.environ)
+		},
+	})
+
+	for _, load := range options.flagSets {
+		ls = append(ls, layer{
+			name:     SourceFlags,
+			location: "",
+			foldCase: false,
+			vars:     nil,
+			load:     func(k *koanf.Koanf) (int, error) { return 0, load(k, options.delim) },
+		})
+	}
+
+	if len(options.overrides) > 0 {
+		ls = append(ls, layer{
+			name:     SourceRuntime,
+			location: "",
+			foldCase: false,
+			vars:     nil,
+			load:     func(k *koanf.Koanf) (int, error) { return 0, loadOverrides(k, options.overrides) },
+		})
+	}
+
+	if values := overrides.Get(); len(values) > 0 {
+		ls = append(ls, layer{
+			name:     SourceTest,
+			location: "",
+			foldCase: false,
+			vars:     nil,
+			load:     func(k *koanf.Koanf) (int, error) { return 0, loadOverrides(k, values) },
+		})
+	}
+
+	return ls
+}
+
+func loadKoanf(k, src *koanf.Koanf) error {
+	if err := k.Load(rawProvider(src.Raw()), nil); err != nil {
+		return fmt.Errorf("load koanf: %w", err)
 	}
 
 	return nil
 }
 
-func loadDotenv(k *koanf.Koanf) error {
-	err := k.Load(file.Provider(".env"), dotenv.ParserEnvWithValue("", "__", envTransform))
-	if err != nil && !errors.Is(err, os.ErrNotExist) {
-		return fmt.Errorf("load dotenv: %w", err)
+func loadOverrides(k *koanf.Koanf, overrides map[string]any) error {
+	for _, key := range slices.Sorted(maps.Keys(overrides)) {
+		if err := k.Set(key, overrides[key]); err != nil {
+			return fmt.Errorf("set %s: %w", key, err)
+		}
 	}
 
 	return nil
 }
 
-func loadEnv(k *koanf.Koanf) error {
-	if err := k.Load(env.Provider("__", env.Opt{
+// prune drops keys that were later replaced by a value of a different shape.
+func prune(p Provenance, k *koanf.Koanf) Provenance {
+	for key := range p {
+		if !k.Exists(key) {
+			delete(p, key)
+		}
+	}
+
+	return p
+}
+
+func loadFromYAML(
+	path string,
+	k *koanf.Koanf,
+	provider func(string) koanf.Provider,
+	parser yamlParser,
+) (int, error) {
+	if path == "" {
+		return 0, nil
+	}
+
+	b, err := provider(path).ReadBytes()
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("load yaml: %w", err)
+	}
+
+	// parsed separately, so missing included files are errors unlike a missing config file
+	m, err := parser.Unmarshal(b)
+	if err != nil {
+		return len(b), fmt.Errorf("load yaml: %w", err)
+	}
+
+	if err := k.Load(rawProvider(m), nil); err != nil {
+		return len(b), fmt.Errorf("load yaml: %w", err)
+	}
+
+	return len(b), nil
+}
+
+func loadDotenv(k *koanf.Koanf, transform envTransformFunc, provider func(string) koanf.Provider) (int, error) {
+	p := &countingProvider{Provider: provider(dotenvPath), n: 0}
+	err := k.Load(p, dotenv.ParserEnvWithValue("", envDelimiter, transform))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return p.n, fmt.Errorf("load dotenv: %w", err)
+	}
+
+	return p.n, nil
+}
+
+// countingProvider records the size of the data read by a byte-based provider.
+type countingProvider struct {
+	koanf.Provider
+
+	n int
+}
+
+func (p *countingProvider) ReadBytes() ([]byte, error) {
+	b, err := p.Provider.ReadBytes()
+	p.n = len(b)
+	return b, err //nolint:wrapcheck // passed through to koanf
+}
+
+func loadEnv(k *koanf.Koanf, transform envTransformFunc, environ map[string]string) error {
+	var environFunc func() []string
+	if environ != nil {
+		environFunc = func() []string {
+			vars := make([]string, 0, len(environ))
+			for _, name := range slices.Sorted(maps.Keys(environ)) {
+				vars = append(vars, name+"="+environ[name])
+			}
+			return vars
+		}
+	}
+
+	if err := k.Load(env.Provider(envDelimiter, env.Opt{
 		Prefix:        "",
-		TransformFunc: envTransform,
-		EnvironFunc:   nil,
+		TransformFunc: transform,
+		EnvironFunc:   environFunc,
 	}), nil); err != nil {
 		return fmt.Errorf("load env: %w", err)
 	}
@@ -85,21 +526,38 @@ func loadEnv(k *koanf.Koanf) error {
 	return nil
 }
 
-func envTransform(k, v string) (string, any) {
-	k = strings.ToLower(k)
-	// JSON object -> map
-	if strings.HasPrefix(v, "{") && strings.HasSuffix(v, "}") {
-		var m map[string]any
-		if err := json.Unmarshal([]byte(v), &m); err == nil {
-			return k, m
-		}
+type envTransformFunc func(k, v string) (string, any)
+
+// recordVars wraps transform to record the variable name of each key in vars, keyed with delim.
+func recordVars(transform envTransformFunc, vars map[string]string, delim string) envTransformFunc {
+	return func(k, v string) (string, any) {
+		key, value := transform(k, v)
+		vars[strings.ReplaceAll(key, envDelimiter, delim)] = k
+		return key, value
 	}
-	// JSON array -> []any
-	if strings.HasPrefix(v, "[") && strings.HasSuffix(v, "]") {
-		var a []any
-		if err := json.Unmarshal([]byte(v), &a); err == nil {
-			return k, a
+}
+
+func envTransform(mapKey func(string) string) envTransformFunc {
+	return func(k, v string) (string, any) {
+		segments := strings.Split(k, envDelimiter)
+		for i, s := range segments {
+			segments[i] = mapKey(s)
+		}
+		k = strings.Join(segments, envDelimiter)
+		// JSON object -> map
+		if strings.HasPrefix(v, "{") && strings.HasSuffix(v, "}") {
+			var m map[string]any
+			if err := json.Unmarshal([]byte(v), &m); err == nil {
+				return k, m
+			}
+		}
+		// JSON array -> []any
+		if strings.HasPrefix(v, "[") && strings.HasSuffix(v, "]") {
+			var a []any
+			if err := json.Unmarshal([]byte(v), &a); err == nil {
+				return k, a
+			}
 		}
+		return k, v
 	}
-	return k, v
 }
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"testing/fstest"
 
 	"github.com/go-core-fx/config"
 	"github.com/stretchr/testify/assert"
@@ -260,3 +261,41 @@ server:
 	assert.Equal(t, 3306, cfg.Database.Port)
 	assert.Equal(t, 9999, cfg.Server.Port) // From environment
 }
+
+// TestLoadUnsupportedTarget tests rejecting targets that are not pointers to structs or maps
+func TestLoadUnsupportedTarget(t *testing.T) {
+	var failed error
+	onLoad := config.OnLoad(func(info config.LoadInfo) { failed = info.Err })
+
+	var n int
+	err := config.Load(&n, config.WithEnviron(nil), onLoad)
+	require.ErrorIs(t, err, config.ErrUnsupportedTarget)
+	assert.Contains(t, err.Error(), "*int")
+	require.ErrorIs(t, failed, config.ErrUnsupportedTarget)
+
+	var c *TestConfig
+	require.ErrorIs(t, config.Load(c, config.WithEnviron(nil)), config.ErrUnsupportedTarget)
+
+	var byID map[int]string
+	require.ErrorIs(t, config.Load(&byID, config.WithEnviron(nil)), config.ErrUnsupportedTarget)
+
+	require.ErrorIs(t, config.LoadAll([]config.Option{config.WithEnviron(nil)}, config.Target("", TestConfig{})),
+		config.ErrUnsupportedTarget)
+}
+
+// TestLoadRaw tests loading into maps without a configuration struct
+func TestLoadRaw(t *testing.T) {
+	files := fstest.MapFS{"config.yaml": {Data: []byte("database:\n  host: db\n  port: 5432\n")}}
+	opts := []config.Option{
+		config.WithFS(files), config.WithLocalYAML("config.yaml"),
+		config.WithEnviron(map[string]string{"DATABASE__PORT": "6432"}),
+	}
+
+	m, err := config.LoadRaw(opts...)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"database": map[string]any{"host": "db", "port": "6432"}}, m)
+
+	var typed map[string]map[string]string
+	require.NoError(t, config.Load(&typed, opts...))
+	assert.Equal(t, map[string]map[string]string{"database": {"host": "db", "port": "6432"}}, typed)
+}
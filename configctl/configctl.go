@@ -0,0 +1,310 @@
+// Package configctl implements a command line tool to check service configuration before deploy.
+//
+// The tool needs the configuration struct and the load options of the service, so each service
+// ships it as a small main package:
+//
+//	func main() {
+//		configctl.Main[Config](config.WithLocalYAML("config.yaml"))
+//	}
+//
+// Usage:
+//
+//	configctl validate [-config path]
+//	configctl render [-config path] [-format yaml|json]
+//	configctl explain [-config path] [key...]
+//	configctl diff -from path -to path [-format text|json]
+package configctl
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/go-core-fx/config"
+)
+
+// Exit codes returned by Run.
+const (
+	ExitOK      = 0
+	ExitInvalid = 1
+	ExitUsage   = 2
+)
+
+const tabPadding = 2
+
+const usage = `usage: configctl <command> [flags]
+
+commands:
+  validate  load the configuration and report errors
+  render    print the effective configuration with secrets masked
+  explain   print every key with its value and the source that set it
+  diff      print the keys that differ between two config files
+`
+
+// Main runs the command given by the process arguments and exits with its exit code.
+func Main[T any](opts ...config.Option) {
+	os.Exit(Run[T](os.Args[1:], os.Stdout, os.Stderr, opts...))
+}
+
+// Run runs the command given by args, loading configuration into a T with the given options,
+// and returns the exit code.
+func Run[T any](args []string, stdout, stderr io.Writer, opts ...config.Option) int {
+	if len(args) == 0 {
+		_, _ = io.WriteString(stderr, usage)
+		return ExitUsage
+	}
+
+	switch args[0] {
+	case "validate":
+		return validate[T](args[1:], stdout, stderr, opts)
+	case "render":
+		return render[T](args[1:], stdout, stderr, opts)
+	case "explain":
+		return explain[T](args[1:], stdout, stderr, opts)
+	case "diff":
+		return diff[T](args[1:], stdout, stderr, opts)
+	case "help", "-h", "-help", "--help":
+		_, _ = io.WriteString(stdout, usage)
+		return ExitOK
+	default:
+		_, _ = fmt.Fprintf(stderr, "unknown command %q\n\n%s", args[0], usage)
+		return ExitUsage
+	}
+}
+
+// validate loads the configuration and reports errors and deprecated keys.
+func validate[T any](args []string, stdout, stderr io.Writer, opts []config.Option) int {
+	fs := newFlagSet("validate", stderr)
+	opts, code, ok := parseFlags(fs, args, stderr, opts)
+	if !ok {
+		return code
+	}
+
+	var info config.LoadInfo
+	opts = append(opts, config.OnLoad(func(i config.LoadInfo) { info = i }))
+
+	if _, err := load[T](opts); err != nil {
+		_, _ = fmt.Fprintf(stderr, "invalid config: %v\n", err)
+		return ExitInvalid
+	}
+
+	for _, d := range info.Deprecated {
+		_, _ = fmt.Fprintf(stderr, "warning: deprecated key %s is set: %s\n", d.Key, d.Hint)
+	}
+	_, _ = fmt.Fprintln(stdout, "config is valid")
+
+	return ExitOK
+}
+
+// render prints the effective configuration with secrets masked.
+func render[T any](args []string, stdout, stderr io.Writer, opts []config.Option) int {
+	fs := newFlagSet("render", stderr)
+	format := fs.String("format", string(config.FormatYAML), "output format: yaml or json")
+	opts, code, ok := parseFlags(fs, args, stderr, opts)
+	if !ok {
+		return code
+	}
+
+	c, err := load[T](opts)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "invalid config: %v\n", err)
+		return ExitInvalid
+	}
+
+	b, err := config.Dump(c, config.Format(*format), config.MarshalOptionsFor(opts...)...)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "render: %v\n", err)
+		return ExitUsage
+	}
+	_, _ = stdout.Write(b)
+
+	return ExitOK
+}
+
+// explain prints the given keys, or all keys, with their masked values and origins.
+func explain[T any](args []string, stdout, stderr io.Writer, opts []config.Option) int {
+	fs := newFlagSet("explain", stderr)
+	opts, code, ok := parseFlags(fs, args, stderr, opts)
+	if !ok {
+		return code
+	}
+
+	var p config.Provenance
+	c, err := load[T](append(opts, config.WithProvenance(&p)))
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "invalid config: %v\n", err)
+		return ExitInvalid
+	}
+
+	values, err := config.Flatten(c, config.MarshalOptionsFor(opts...)...)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "explain: %v\n", err)
+		return ExitInvalid
+	}
+
+	keys := fs.Args()
+	if len(keys) == 0 {
+		keys = slices.Sorted(maps.Keys(values))
+	}
+
+	w := tabwriter.NewWriter(stdout, 0, 0, tabPadding, ' ', 0)
+	for _, key := range keys {
+		value, set := values[key]
+		origin := "default"
+		if o, ok := p.Lookup(key); ok {
+			origin = o.String()
+		} else if !set {
+			origin = "unknown key"
+		}
+
+		b, _ := json.Marshal(value)
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", key, b, origin)
+	}
+	_ = w.Flush()
+
+	return ExitOK
+}
+
+// diff prints the keys that differ between the configurations loaded from two files,
+// with the same environment and service options.
+func diff[T any](args []string, stdout, stderr io.Writer, opts []config.Option) int {
+	fs := newFlagSet("diff", stderr)
+	from := fs.String("from", "", "path to the YAML config file to compare from, e.g. staging")
+	to := fs.String("to", "", "path to the YAML config file to compare to, e.g. production")
+	format := fs.String("format", "text", "output format: text or json")
+	if code, ok := parse(fs, args); !ok {
+		return code
+	}
+
+	if *from == "" || *to == "" {
+		_, _ = io.WriteString(stderr, "diff: both -from and -to are required\n")
+		return ExitUsage
+	}
+
+	var configs [2]*T
+	for i, path := range []string{*from, *to} {
+		o, code, ok := withFile(opts, path, stderr)
+		if !ok {
+			return code
+		}
+
+		c, err := load[T](o)
+		if err != nil {
+			_, _ = fmt.Fprintf(stderr, "invalid config %s: %v\n", path, err)
+			return ExitInvalid
+		}
+		configs[i] = c
+	}
+
+	changes, err := config.Diff(*configs[0], *configs[1], config.MarshalOptionsFor(opts...)...)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "diff: %v\n", err)
+		return ExitInvalid
+	}
+
+	switch *format {
+	case "text":
+		writeChanges(stdout, changes)
+	case "json":
+		writeChangesJSON(stdout, changes)
+	default:
+		_, _ = fmt.Fprintf(stderr, "diff: unsupported format %q\n", *format)
+		return ExitUsage
+	}
+
+	return ExitOK
+}
+
+// writeChanges prints one line per change: `+` for added, `-` for removed and `~` for changed keys.
+func writeChanges(w io.Writer, changes []config.Change) {
+	for _, c := range changes {
+		oldValue, _ := json.Marshal(c.Old)
+		newValue, _ := json.Marshal(c.New)
+		switch {
+		case c.Old == nil:
+			_, _ = fmt.Fprintf(w, "+ %s: %s\n", c.Key, newValue)
+		case c.New == nil:
+			_, _ = fmt.Fprintf(w, "- %s: %s\n", c.Key, oldValue)
+		default:
+			_, _ = fmt.Fprintf(w, "~ %s: %s -> %s\n", c.Key, oldValue, newValue)
+		}
+	}
+}
+
+type jsonChange struct {
+	Key    string `json:"key"`
+	Old    any    `json:"old"`
+	New    any    `json:"new"`
+	Secret bool   `json:"secret"`
+}
+
+func writeChangesJSON(w io.Writer, changes []config.Change) {
+	out := make([]jsonChange, 0, len(changes))
+	for _, c := range changes {
+		out = append(out, jsonChange{Key: c.Key, Old: c.Old, New: c.New, Secret: c.Secret})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(out)
+}
+
+func load[T any](opts []config.Option) (*T, error) {
+	c := new(T)
+	if err := config.Load(c, opts...); err != nil {
+		return nil, err //nolint:wrapcheck // reported as is
+	}
+
+	return c, nil
+}
+
+func newFlagSet(name string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	return fs
+}
+
+// parseFlags adds the common flags of all commands to fs, parses args and returns the resulting
+// load options. If the command should not run, it returns false and the exit code.
+func parseFlags(fs *flag.FlagSet, args []string, stderr io.Writer, opts []config.Option) ([]config.Option, int, bool) {
+	path := fs.String("config", "", "path to the YAML config file, overriding the service default")
+
+	if code, ok := parse(fs, args); !ok {
+		return nil, code, false
+	}
+
+	return withFile(opts, *path, stderr)
+}
+
+func parse(fs *flag.FlagSet, args []string) (int, bool) {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return ExitOK, false
+		}
+		return ExitUsage, false
+	}
+
+	return ExitOK, true
+}
+
+// withFile returns a copy of opts loading the given YAML file, if any.
+func withFile(opts []config.Option, path string, stderr io.Writer) ([]config.Option, int, bool) {
+	opts = append([]config.Option(nil), opts...)
+	if path == "" {
+		return opts, ExitOK, true
+	}
+
+	// Load skips missing files, which would hide a typo in the path here
+	if _, err := os.Stat(path); err != nil {
+		_, _ = fmt.Fprintf(stderr, "invalid config: %v\n", err)
+		return nil, ExitInvalid, false
+	}
+
+	return append(opts, config.WithLocalYAML(path)), ExitOK, true
+}
@@ -0,0 +1,181 @@
+package configctl_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-core-fx/config"
+	"github.com/go-core-fx/config/configctl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testConfig struct {
+	Server struct {
+		Host string `koanf:"host"`
+		Port int    `koanf:"port"`
+	} `koanf:"server"`
+	Password config.Secret `koanf:"password"`
+	Mode     string        `koanf:"mode"     oneof:"dev,prod"`
+	Legacy   string        `koanf:"legacy"   deprecated:"use mode"`
+}
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	p := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(p, []byte(content), 0o644))
+	return p
+}
+
+func run(t *testing.T, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := configctl.Run[testConfig](args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+// TestValidate tests the validate command on valid and invalid files
+func TestValidate(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	valid := writeFile(t, tmpDir, "valid.yaml", "server:\n  host: localhost\n  port: 8080\nmode: prod\nlegacy: x\n")
+	invalid := writeFile(t, tmpDir, "invalid.yaml", "mode: staging\n")
+
+	code, stdout, stderr := run(t, "validate", "-config", valid)
+	assert.Equal(t, configctl.ExitOK, code)
+	assert.Equal(t, "config is valid\n", stdout)
+	assert.Equal(t, "warning: deprecated key legacy is set: use mode\n", stderr)
+
+	code, _, stderr = run(t, "validate", "-config", invalid)
+	assert.Equal(t, configctl.ExitInvalid, code)
+	assert.Contains(t, stderr, `mode: "staging" is not one of ["dev" "prod"]`)
+
+	code, _, stderr = run(t, "validate", "-config", filepath.Join(tmpDir, "missing.yaml"))
+	assert.Equal(t, configctl.ExitInvalid, code)
+	assert.Contains(t, stderr, "missing.yaml")
+}
+
+// TestRunUsage tests usage errors
+func TestRunUsage(t *testing.T) {
+	code, _, stderr := run(t)
+	assert.Equal(t, configctl.ExitUsage, code)
+	assert.Contains(t, stderr, "usage: configctl")
+
+	code, _, stderr = run(t, "frobnicate")
+	assert.Equal(t, configctl.ExitUsage, code)
+	assert.Contains(t, stderr, `unknown command "frobnicate"`)
+
+	code, _, _ = run(t, "validate", "-unknown")
+	assert.Equal(t, configctl.ExitUsage, code)
+}
+
+// TestRender tests printing the effective configuration with secrets masked
+func TestRender(t *testing.T) {
+	t.Setenv("SERVER__PORT", "9090")
+
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	path := writeFile(t, tmpDir, "config.yaml", "server:\n  host: localhost\n  port: 8080\npassword: s3cr3t\n")
+
+	code, stdout, _ := run(t, "render", "-config", path, "-format", "json")
+	assert.Equal(t, configctl.ExitOK, code)
+	assert.JSONEq(t, `{"server":{"host":"localhost","port":9090},"password":"******","mode":"","legacy":""}`, stdout)
+
+	code, _, stderr := run(t, "render", "-config", path, "-format", "toml")
+	assert.Equal(t, configctl.ExitUsage, code)
+	assert.Contains(t, stderr, "unsupported format")
+}
+
+// TestExplain tests printing keys with their values and origins
+func TestExplain(t *testing.T) {
+	t.Setenv("SERVER__PORT", "9090")
+
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	path := writeFile(t, tmpDir, "config.yaml", "server:\n  host: localhost\n  port: 8080\npassword: s3cr3t\n")
+
+	code, stdout, _ := run(t, "explain", "-config", path)
+	assert.Equal(t, configctl.ExitOK, code)
+	assert.Equal(t, `legacy       ""           default
+mode         ""           default
+password     "******"     yaml (`+path+`)
+server.host  "localhost"  yaml (`+path+`)
+server.port  9090         env (SERVER__PORT)
+`, stdout)
+
+	code, stdout, _ = run(t, "explain", "-config", path, "server.port", "server.nope")
+	assert.Equal(t, configctl.ExitOK, code)
+	assert.Equal(t, "server.port  9090  env (SERVER__PORT)\nserver.nope  null  unknown key\n", stdout)
+}
+
+// TestExplainOptions tests that render and explain use the tags and key delimiter of the service
+func TestExplainOptions(t *testing.T) {
+	type taggedConfig struct {
+		Server struct {
+			DBHost string `json:"db_host"`
+		} `json:"server"`
+	}
+
+	t.Chdir(t.TempDir())
+	opts := []config.Option{
+		config.WithTag("json"),
+		config.WithKeyDelimiter("/"),
+		config.WithEnviron(map[string]string{"SERVER__DB_HOST": "h"}),
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := configctl.Run[taggedConfig]([]string{"render"}, &stdout, &stderr, opts...)
+	assert.Equal(t, configctl.ExitOK, code, stderr.String())
+	assert.Equal(t, "server:\n    db_host: h\n", stdout.String())
+
+	stdout.Reset()
+	code = configctl.Run[taggedConfig]([]string{"explain"}, &stdout, &stderr, opts...)
+	assert.Equal(t, configctl.ExitOK, code, stderr.String())
+	assert.Equal(t, "server/db_host  \"h\"  env (SERVER__DB_HOST)\n", stdout.String())
+}
+
+// TestDiff tests comparing two config files
+func TestDiff(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	staging := writeFile(t, tmpDir, "staging.yaml", "server:\n  host: staging.local\n  port: 8080\npassword: one\nmode: dev\n")
+	prod := writeFile(t, tmpDir, "prod.yaml", "server:\n  host: prod.local\n  port: 8080\npassword: two\nmode: prod\n")
+
+	code, stdout, _ := run(t, "diff", "-from", staging, "-to", prod)
+	assert.Equal(t, configctl.ExitOK, code)
+	assert.Equal(t, `~ mode: "dev" -> "prod"
+~ password: "******" -> "******"
+~ server.host: "staging.local" -> "prod.local"
+`, stdout)
+
+	code, stdout, _ = run(t, "diff", "-from", staging, "-to", prod, "-format", "json")
+	assert.Equal(t, configctl.ExitOK, code)
+	assert.JSONEq(t, `[
+		{"key": "mode", "old": "dev", "new": "prod", "secret": false},
+		{"key": "password", "old": "******", "new": "******", "secret": true},
+		{"key": "server.host", "old": "staging.local", "new": "prod.local", "secret": false}
+	]`, stdout)
+
+	code, _, stderr := run(t, "diff", "-from", staging)
+	assert.Equal(t, configctl.ExitUsage, code)
+	assert.Contains(t, stderr, "both -from and -to are required")
+}
+
+// TestDiffOptions tests that diff uses the tags of the service
+func TestDiffOptions(t *testing.T) {
+	type taggedConfig struct {
+		DBHost string `json:"db_host"`
+	}
+
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	staging := writeFile(t, tmpDir, "staging.yaml", "db_host: staging.local\n")
+	prod := writeFile(t, tmpDir, "prod.yaml", "db_host: prod.local\n")
+
+	var stdout, stderr bytes.Buffer
+	code := configctl.Run[taggedConfig]([]string{"diff", "-from", staging, "-to", prod}, &stdout, &stderr, config.WithTag("json"))
+	assert.Equal(t, configctl.ExitOK, code, stderr.String())
+	assert.Equal(t, "~ db_host: \"staging.local\" -> \"prod.local\"\n", stdout.String())
+}
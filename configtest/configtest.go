@@ -0,0 +1,110 @@
+// Package configtest provides helpers for testing code that loads configuration.
+package configtest
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-core-fx/config"
+	"github.com/go-core-fx/config/internal/overrides"
+	"github.com/knadh/koanf/parsers/yaml"
+)
+
+const (
+	// SourceInline is the source name of values passed to LoadFromString and LoadFromMap.
+	SourceInline = "inline"
+
+	goldenFileMode = 0o644
+)
+
+//nolint:gochecknoglobals // test flag
+var update = flag.Bool("configtest.update", false, "update configtest golden files")
+
+// Override sets the given keys, e.g. "database.host", for every Load until the test and its subtests finish.
+//
+// Overrides take precedence over all sources and nest: a later Override adds to earlier ones.
+// They apply process-wide, so Override must not be used in parallel tests.
+func Override(t testing.TB, values map[string]any) {
+	t.Helper()
+
+	restore := overrides.Set(values)
+	t.Cleanup(restore)
+}
+
+// LoadFromString loads a T from inline YAML or JSON, merged like a file passed to WithLocalYAML.
+//
+// The given options are applied as for Load, so `.env` and environment variables still take precedence.
+func LoadFromString[T any](data string, opts ...config.Option) (T, error) {
+	m, err := yaml.Parser().Unmarshal([]byte(data))
+	if err != nil {
+		var c T
+		return c, fmt.Errorf("parse: %w", err)
+	}
+
+	return LoadFromMap[T](m, opts...)
+}
+
+// LoadFromMap loads a T from a nested map, such as {"database": {"host": "localhost"}}.
+//
+// The given options are applied as for Load, so `.env` and environment variables still take precedence.
+func LoadFromMap[T any](m map[string]any, opts ...config.Option) (T, error) {
+	var c T
+	err := config.Load(&c, append([]config.Option{config.WithSource(mapSource(m))}, opts...)...)
+
+	return c, err //nolint:wrapcheck // errors are those of Load
+}
+
+type mapSource map[string]any
+
+func (mapSource) Name() string {
+	return SourceInline
+}
+
+func (s mapSource) Load(context.Context) (map[string]any, error) {
+	return s, nil
+}
+
+// Golden loads a T with the given options and compares the effective configuration, with secrets redacted,
+// against the golden file at path. Files ending in .json are compared as JSON, others as YAML.
+//
+// Run the test with -configtest.update to write the current configuration to the golden file.
+func Golden[T any](t testing.TB, path string, opts ...config.Option) {
+	t.Helper()
+
+	var c T
+	if err := config.Load(&c, opts...); err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+
+	format := config.FormatYAML
+	if filepath.Ext(path) == ".json" {
+		format = config.FormatJSON
+	}
+
+	got, err := config.Dump(&c, format)
+	if err != nil {
+		t.Fatalf("dump config: %v", err)
+	}
+
+	if *update {
+		if err := os.WriteFile(path, got, goldenFileMode); err != nil {
+			t.Fatalf("update golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden file (run with -configtest.update to create it): %v", err)
+	}
+
+	if !bytes.Equal(want, got) {
+		t.Errorf("effective configuration differs from %s (run with -configtest.update to accept):\n--- want\n%s\n+++ got\n%s",
+			path, want, got)
+	}
+}
@@ -0,0 +1,105 @@
+package configtest_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-core-fx/config"
+	"github.com/go-core-fx/config/configtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testConfig struct {
+	Server struct {
+		Host string `koanf:"host"`
+		Port int    `koanf:"port"`
+	} `koanf:"server"`
+}
+
+// TestOverride tests that overrides apply on top of the environment and are restored after the test
+func TestOverride(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("SERVER__HOST", "env")
+
+	t.Run("override", func(t *testing.T) {
+		configtest.Override(t, map[string]any{"server.host": "localhost", "server.port": 8080})
+
+		t.Run("nested", func(t *testing.T) {
+			configtest.Override(t, map[string]any{"server.port": 9090})
+
+			var c testConfig
+			require.NoError(t, config.Load(&c))
+			assert.Equal(t, "localhost", c.Server.Host)
+			assert.Equal(t, 9090, c.Server.Port)
+		})
+
+		var c testConfig
+		var p config.Provenance
+		require.NoError(t, config.Load(&c, config.WithProvenance(&p)))
+		assert.Equal(t, "localhost", c.Server.Host)
+		assert.Equal(t, 8080, c.Server.Port)
+		assert.Equal(t, config.SourceTest, p["server.host"].Source)
+	})
+
+	var c testConfig
+	require.NoError(t, config.Load(&c))
+	assert.Equal(t, "env", c.Server.Host)
+	assert.Zero(t, c.Server.Port)
+}
+
+// TestLoadFromString tests loading inline YAML and JSON
+func TestLoadFromString(t *testing.T) {
+	c, err := configtest.LoadFromString[testConfig]("server:\n  host: localhost\n  port: 8080\n")
+	require.NoError(t, err)
+	assert.Equal(t, "localhost", c.Server.Host)
+	assert.Equal(t, 8080, c.Server.Port)
+
+	c, err = configtest.LoadFromString[testConfig](`{"server": {"port": "9090"}}`)
+	require.NoError(t, err)
+	assert.Equal(t, 9090, c.Server.Port)
+
+	_, err = configtest.LoadFromString[testConfig]("server: [")
+	require.Error(t, err)
+
+	_, err = configtest.LoadFromString[testConfig]("server:\n  port: many\n")
+	require.Error(t, err)
+}
+
+// TestLoadFromMap tests loading a nested map with provenance
+func TestLoadFromMap(t *testing.T) {
+	var p config.Provenance
+	c, err := configtest.LoadFromMap[testConfig](
+		map[string]any{"server": map[string]any{"host": "localhost"}},
+		config.WithProvenance(&p),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "localhost", c.Server.Host)
+	assert.Equal(t, configtest.SourceInline, p["server.host"].Source)
+}
+
+type recorder struct {
+	testing.TB
+
+	failed bool
+}
+
+func (r *recorder) Errorf(string, ...any) {
+	r.failed = true
+}
+
+// TestGolden tests comparing the effective configuration against a golden file
+func TestGolden(t *testing.T) {
+	t.Chdir(t.TempDir())
+	golden := filepath.Join(t.TempDir(), "config.golden.yaml")
+	require.NoError(t, os.WriteFile(golden, []byte("server:\n    host: localhost\n    port: 8080\n"), 0o644))
+
+	configtest.Override(t, map[string]any{"server.host": "localhost", "server.port": 8080})
+	configtest.Golden[testConfig](t, golden)
+
+	configtest.Override(t, map[string]any{"server.port": 9090})
+	r := &recorder{TB: t, failed: false}
+	configtest.Golden[testConfig](r, golden)
+	assert.True(t, r.failed)
+}
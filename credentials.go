@@ -0,0 +1,67 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	kmaps "github.com/knadh/koanf/maps"
+	"github.com/knadh/koanf/v2"
+)
+
+const credentialsDirEnv = "CREDENTIALS_DIRECTORY"
+
+// credentialsDir returns the directory of systemd credentials passed to the service, if any.
+func (o *options) credentialsDir() string {
+	dir, _ := o.lookupEnv(credentialsDirEnv)
+	return dir
+}
+
+// loadCredentials loads one key per file in dir, named like environment variables,
+// e.g. DATABASE__PASSWORD sets database.password to the contents of the file.
+func loadCredentials(k *koanf.Koanf, dir string, options *options) (int, error) {
+	if dir == "" {
+		return 0, nil
+	}
+
+	var entries []fs.DirEntry
+	var err error
+	if options.fsys != nil {
+		entries, err = fs.ReadDir(options.fsys, dir)
+	} else {
+		entries, err = os.ReadDir(dir)
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("load credentials: %w", err)
+	}
+
+	transform := envTransform(options.mapKey)
+	flat := map[string]any{}
+	size := 0
+	for _, e := range entries {
+		if e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+
+		b, err := options.fileProvider(filepath.Join(dir, e.Name())).ReadBytes()
+		if err != nil {
+			return size, fmt.Errorf("load credentials: %s: %w", e.Name(), err)
+		}
+		size += len(b)
+
+		key, _ := transform(e.Name(), "")
+		flat[key] = strings.TrimSuffix(strings.TrimSuffix(string(b), "\n"), "\r")
+	}
+
+	if err := k.Load(rawProvider(kmaps.Unflatten(flat, envDelimiter)), nil); err != nil {
+		return size, fmt.Errorf("load credentials: %w", err)
+	}
+
+	return size, nil
+}
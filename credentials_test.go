@@ -0,0 +1,35 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWithSystemdCredentials tests loading one key per credential file, overridden by environment variables
+func TestWithSystemdCredentials(t *testing.T) {
+	t.Chdir(t.TempDir())
+	dir := t.TempDir()
+	writeTempFile(t, dir, "DATABASE__PASSWORD", "s3cr3t\n")
+	writeTempFile(t, dir, "database__username", "admin")
+	writeTempFile(t, dir, "DATABASE__HOST", "credentials")
+
+	var c TestConfig
+	var p config.Provenance
+	require.NoError(t, config.Load(&c,
+		config.WithSystemdCredentials(),
+		config.WithEnviron(map[string]string{"CREDENTIALS_DIRECTORY": dir, "DATABASE__HOST": "env"}),
+		config.WithProvenance(&p),
+	))
+	assert.Equal(t, "s3cr3t", c.Database.Password)
+	assert.Equal(t, "admin", c.Database.Username)
+	assert.Equal(t, "env", c.Database.Host)
+	assert.Equal(t, config.Origin{Source: config.SourceCredentials, Location: dir}, p["database.password"])
+
+	// outside of systemd
+	c = TestConfig{}
+	require.NoError(t, config.Load(&c, config.WithSystemdCredentials(), config.WithEnviron(nil)))
+	assert.Empty(t, c.Database.Password)
+}
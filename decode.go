@@ -0,0 +1,255 @@
+package config
+
+import (
+	"encoding"
+	"fmt"
+	"maps"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/knadh/koanf/v2"
+)
+
+// unmarshalConf returns the koanf unmarshal configuration used by Load.
+//
+// It keeps the koanf defaults (weakly typed input, string to time.Duration and
+// encoding.TextUnmarshaler support) and adds the hooks of this package.
+func unmarshalConf(options *options) koanf.UnmarshalConf {
+	return koanf.UnmarshalConf{
+		Tag:           options.tags[0],
+		FlatPaths:     options.unmarshal != nil && options.unmarshal.FlatPaths,
+		DecoderConfig: decoderConfig(options, nil),
+	}
+}
+
+func decoderConfig(options *options, result any) *mapstructure.DecoderConfig {
+	hook := decodeHook(options)
+	if options.unmarshal != nil && options.unmarshal.DecoderConfig != nil {
+		c := *options.unmarshal.DecoderConfig
+		if c.DecodeHook == nil {
+			c.DecodeHook = hook
+		}
+		if options.weakInput {
+			c.DecodeHook = mapstructure.ComposeDecodeHookFunc(stringToBoolHook(), c.DecodeHook)
+			c.WeaklyTypedInput = true
+		}
+		c.Result = result
+		c.TagName = options.tags[0]
+		c.Squash = true // embedded structs are always squashed, see fieldKey
+
+		return &c
+	}
+
+	return &mapstructure.DecoderConfig{
+		DecodeHook:       hook,
+		Metadata:         nil,
+		Result:           result,
+		Squash:           true,
+		TagName:          options.tags[0],
+		WeaklyTypedInput: true,
+	}
+}
+
+// decodeHook composes the fallback tag hook, the user hooks and the built-in hooks, in that order.
+func decodeHook(options *options) mapstructure.DecodeHookFunc {
+	var hooks []mapstructure.DecodeHookFunc
+	if len(options.tags) > 1 {
+		hooks = append(hooks, fallbackTagHook(options.tags))
+	}
+	hooks = append(hooks, options.decodeHooks...)
+	if options.weakInput {
+		hooks = append(hooks, stringToBoolHook())
+	}
+	hooks = append(hooks,
+		optionalHook(options),
+		orderedMapHook(options),
+		stringListHook(options.listSep),
+		mapstructure.StringToTimeDurationHookFunc(),
+		stringToTimeHook(options.timeLayouts),
+		timeToTextHook(),
+		scalarToTextHook(),
+		mapstructure.TextUnmarshallerHookFunc(),
+	)
+	if options.splitSlices {
+		hooks = append(hooks, sliceSplitHook(options.listSep))
+	}
+
+	return mapstructure.ComposeDecodeHookFunc(hooks...)
+}
+
+// decode decodes data into result with the same configuration as Load.
+func decode(options *options, data, result any) error {
+	d, err := mapstructure.NewDecoder(decoderConfig(options, result))
+	if err != nil {
+		return fmt.Errorf("new decoder: %w", err)
+	}
+
+	return d.Decode(data)
+}
+
+// optionalHook decodes values into Optional fields and marks them as set.
+func optionalHook(options *options) mapstructure.DecodeHookFuncType {
+	return func(_, t reflect.Type, data any) (any, error) {
+		if !reflect.PointerTo(t).Implements(reflect.TypeFor[optional]()) {
+			return data, nil
+		}
+
+		result := reflect.New(t)
+		o, _ := result.Interface().(optional)
+		if err := o.decodeOptional(data, func(in, out any) error { return decode(options, in, out) }); err != nil {
+			return nil, err
+		}
+
+		return result.Elem().Interface(), nil
+	}
+}
+
+// orderedMapHook decodes maps into OrderedMap fields, with keys in lexical order until applyKeyOrder.
+func orderedMapHook(options *options) mapstructure.DecodeHookFuncType {
+	return func(_, t reflect.Type, data any) (any, error) {
+		if !reflect.PointerTo(t).Implements(reflect.TypeFor[orderedMap]()) {
+			return data, nil
+		}
+
+		result := reflect.New(t)
+		m, _ := result.Interface().(orderedMap)
+		if err := m.decodeOrdered(data, func(in, out any) error { return decode(options, in, out) }); err != nil {
+			return nil, err
+		}
+
+		return result.Elem().Interface(), nil
+	}
+}
+
+// timeToTextHook converts time.Time values, produced by the YAML parser for unquoted
+// timestamps, into RFC 3339 strings when the target implements encoding.TextUnmarshaler.
+func timeToTextHook() mapstructure.DecodeHookFuncType {
+	return func(f, t reflect.Type, data any) (any, error) {
+		ts, ok := data.(time.Time)
+		if !ok || t == reflect.TypeFor[time.Time]() {
+			return data, nil
+		}
+
+		if !reflect.PointerTo(t).Implements(reflect.TypeFor[encoding.TextUnmarshaler]()) {
+			return data, nil
+		}
+
+		return ts.Format(time.RFC3339Nano), nil
+	}
+}
+
+// stringToBoolHook converts the spellings of booleans commonly used in environment variables,
+// such as yes, no, on and off in any case, into booleans.
+func stringToBoolHook() mapstructure.DecodeHookFuncType {
+	return func(f, t reflect.Type, data any) (any, error) {
+		if f.Kind() != reflect.String || t.Kind() != reflect.Bool {
+			return data, nil
+		}
+
+		switch strings.ToLower(strings.TrimSpace(reflect.ValueOf(data).String())) {
+		case "1", "t", "true", "y", "yes", "on":
+			return true, nil
+		case "", "0", "f", "false", "n", "no", "off":
+			return false, nil
+		default:
+			return data, nil
+		}
+	}
+}
+
+// scalarToTextHook converts numbers and booleans, produced by the YAML parser for unquoted
+// scalars such as `price: 1.50`, into strings when a non-numeric target implements
+// encoding.TextUnmarshaler, e.g. decimal or IP address types.
+func scalarToTextHook() mapstructure.DecodeHookFuncType {
+	return func(f, t reflect.Type, data any) (any, error) {
+		if !isScalar(f.Kind()) || isScalar(t.Kind()) {
+			return data, nil
+		}
+
+		if !reflect.PointerTo(t).Implements(reflect.TypeFor[encoding.TextUnmarshaler]()) {
+			return data, nil
+		}
+
+		return fmt.Sprint(data), nil
+	}
+}
+
+func isScalar(k reflect.Kind) bool {
+	switch k {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// stringToTimeHook parses strings into time.Time using the first matching layout.
+func stringToTimeHook(layouts []string) mapstructure.DecodeHookFuncType {
+	return func(f, t reflect.Type, data any) (any, error) {
+		if f.Kind() != reflect.String || t != reflect.TypeFor[time.Time]() {
+			return data, nil
+		}
+
+		s := reflect.ValueOf(data).String()
+		if s == "" {
+			return time.Time{}, nil
+		}
+
+		for _, layout := range layouts {
+			if ts, err := time.Parse(layout, s); err == nil {
+				return ts, nil
+			}
+		}
+
+		return nil, fmt.Errorf("%w: %q does not match any of %q", ErrInvalidTime, s, layouts)
+	}
+}
+
+// fallbackTagHook renames keys for struct fields without the primary tag but with one of
+// the fallback tags to the field name, which mapstructure then matches.
+func fallbackTagHook(tags []string) mapstructure.DecodeHookFuncType {
+	return func(_, t reflect.Type, data any) (any, error) {
+		m, ok := data.(map[string]any)
+		if !ok || t.Kind() != reflect.Struct {
+			return data, nil
+		}
+
+		out := maps.Clone(m)
+		renameFallbackKeys(out, m, t, tags, 0)
+
+		return out, nil
+	}
+}
+
+func renameFallbackKeys(out, m map[string]any, t reflect.Type, tags []string, depth int) {
+	if depth > maxFieldDepth {
+		return
+	}
+
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, squash := fieldKey(f, tags)
+		if squash && indirect(f.Type).Kind() == reflect.Struct {
+			renameFallbackKeys(out, m, indirect(f.Type), tags, depth+1)
+			continue
+		}
+
+		if _, ok := f.Tag.Lookup(tags[0]); ok {
+			continue
+		}
+
+		if v, ok := m[name]; ok && name != "-" && name != f.Name {
+			delete(out, name)
+			out[f.Name] = v
+		}
+	}
+}
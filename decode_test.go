@@ -0,0 +1,92 @@
+package config_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-core-fx/config"
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/knadh/koanf/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type priority int
+
+type priorityConfig struct {
+	Queue struct {
+		Name     string   `koanf:"name"`
+		Priority priority `koanf:"priority"`
+	} `koanf:"queue"`
+}
+
+// TestWithDecodeHook tests that custom decode hooks run before the built-in ones
+func TestWithDecodeHook(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	yamlFile := writeTempFile(t, tmpDir, "config.yaml", "queue:\n  name: jobs\n  priority: high\n")
+
+	hook := func(f, t reflect.Type, data any) (any, error) {
+		if f.Kind() != reflect.String || t != reflect.TypeFor[priority]() {
+			return data, nil
+		}
+		return map[string]priority{"low": 1, "high": 3}[strings.ToLower(data.(string))], nil
+	}
+
+	var cfg priorityConfig
+	err := config.Load(&cfg, config.WithLocalYAML(yamlFile), config.WithDecodeHook(mapstructure.DecodeHookFuncType(hook)))
+	require.NoError(t, err)
+	assert.Equal(t, "jobs", cfg.Queue.Name)
+	assert.Equal(t, priority(3), cfg.Queue.Priority)
+}
+
+// TestWithUnmarshalConf tests that decoder settings such as ErrorUnused are passed through
+func TestWithUnmarshalConf(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	yamlFile := writeTempFile(t, tmpDir, "config.yaml", "queue:\n  name: jobs\n  nmae: typo\n")
+
+	var cfg priorityConfig
+	require.NoError(t, config.Load(&cfg, config.WithLocalYAML(yamlFile)))
+	assert.Equal(t, "jobs", cfg.Queue.Name)
+
+	conf := koanf.UnmarshalConf{
+		Tag:       "",
+		FlatPaths: false,
+		DecoderConfig: &mapstructure.DecoderConfig{
+			ErrorUnused:      true,
+			WeaklyTypedInput: true,
+		},
+	}
+	err := config.Load(&cfg, config.WithLocalYAML(yamlFile), config.WithUnmarshalConf(conf))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "nmae")
+}
+
+type weakConfig struct {
+	Debug   bool `koanf:"debug"`
+	Verbose bool `koanf:"verbose"`
+	Port    int  `koanf:"port"`
+}
+
+// TestWithWeaklyTypedInput tests coercing strings even with a strict decoder config
+func TestWithWeaklyTypedInput(t *testing.T) {
+	environ := config.WithEnviron(map[string]string{"DEBUG": "Yes", "VERBOSE": "off", "PORT": "8080"})
+	strict := config.WithUnmarshalConf(koanf.UnmarshalConf{
+		Tag:           "",
+		FlatPaths:     false,
+		DecoderConfig: &mapstructure.DecoderConfig{WeaklyTypedInput: false},
+	})
+
+	var c weakConfig
+	require.Error(t, config.Load(&c, environ, strict))
+
+	c = weakConfig{}
+	require.NoError(t, config.Load(&c, environ, strict, config.WithWeaklyTypedInput()))
+	assert.Equal(t, weakConfig{Debug: true, Verbose: false, Port: 8080}, c)
+
+	c = weakConfig{}
+	require.NoError(t, config.Load(&c, config.WithEnviron(map[string]string{"DEBUG": "1"}), config.WithWeaklyTypedInput()))
+	assert.True(t, c.Debug)
+}
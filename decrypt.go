@@ -0,0 +1,74 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+
+	"github.com/knadh/koanf/v2"
+)
+
+// decrypter replaces matching encrypted values with their plaintext.
+type decrypter struct {
+	name    string
+	match   func(value string) bool
+	decrypt Decryptor
+}
+
+// Decryptor decrypts a single configuration value.
+type Decryptor func(ctx context.Context, ciphertext string) (string, error)
+
+// SecretResolver resolves a secret reference, such as `kv/app#api_key`, to the secret value.
+type SecretResolver func(ctx context.Context, ref string) (string, error)
+
+// EncPattern matches values of the form ENC[ciphertext] and captures the ciphertext.
+const EncPattern = `^ENC\[(.*)\]$`
+
+// patternDecrypter applies fn to values matching pattern.
+// If the pattern has a capture group, only the first group is passed to fn.
+func patternDecrypter(pattern *regexp.Regexp, fn Decryptor) decrypter {
+	return decrypter{
+		name:  "decryptor",
+		match: pattern.MatchString,
+		decrypt: func(ctx context.Context, value string) (string, error) {
+			if m := pattern.FindStringSubmatch(value); len(m) > 1 {
+				value = m[1]
+			}
+			return fn(ctx, value)
+		},
+	}
+}
+
+// decryptValues decrypts every string value in k matched by one of the decrypters.
+func decryptValues(ctx context.Context, k *koanf.Koanf, decrypters []decrypter) error {
+	if len(decrypters) == 0 {
+		return nil
+	}
+
+	all := k.All()
+	for _, key := range slices.Sorted(maps.Keys(all)) {
+		s, ok := all[key].(string)
+		if !ok {
+			continue
+		}
+
+		for _, d := range decrypters {
+			if !d.match(s) {
+				continue
+			}
+
+			plain, err := d.decrypt(ctx, s)
+			if err != nil {
+				return fmt.Errorf("decrypt %s: %s: %w", key, d.name, err)
+			}
+			if err := k.Set(key, plain); err != nil {
+				return fmt.Errorf("decrypt %s: %w", key, err)
+			}
+			break
+		}
+	}
+
+	return nil
+}
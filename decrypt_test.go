@@ -0,0 +1,55 @@
+package config_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ctxKey struct{}
+
+// TestWithDecryptor tests decryption of values matching a pattern
+func TestWithDecryptor(t *testing.T) {
+	t.Setenv("DATABASE__PASSWORD", "ENC[terces]")
+	t.Setenv("DATABASE__USERNAME", "plain-user")
+	t.Chdir(t.TempDir())
+
+	var seen []any
+	reverse := func(ctx context.Context, ciphertext string) (string, error) {
+		seen = append(seen, ctx.Value(ctxKey{}))
+		r := []rune(ciphertext)
+		for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+			r[i], r[j] = r[j], r[i]
+		}
+		return string(r), nil
+	}
+
+	ctx := context.WithValue(t.Context(), ctxKey{}, "marker")
+	var cfg TestConfig
+	err := config.LoadContext(ctx, &cfg, config.WithDecryptor(regexp.MustCompile(config.EncPattern), reverse))
+	require.NoError(t, err)
+
+	assert.Equal(t, "secret", cfg.Database.Password)
+	assert.Equal(t, "plain-user", cfg.Database.Username)
+	assert.Equal(t, []any{"marker"}, seen)
+}
+
+// TestWithDecryptorError tests decryptor error propagation
+func TestWithDecryptorError(t *testing.T) {
+	t.Setenv("DATABASE__PASSWORD", "kms:abc")
+	t.Chdir(t.TempDir())
+
+	errKMS := errors.New("kms unavailable")
+	var cfg TestConfig
+	err := config.Load(&cfg, config.WithDecryptor(regexp.MustCompile(`^kms:`), func(context.Context, string) (string, error) {
+		return "", errKMS
+	}))
+	require.ErrorIs(t, err, errKMS)
+	assert.True(t, strings.HasPrefix(err.Error(), "decrypt database.password"))
+}
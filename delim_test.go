@@ -0,0 +1,64 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type topicConfig struct {
+	Kafka struct {
+		Topics map[string]struct {
+			Partitions int `koanf:"partitions"`
+		} `koanf:"topics"`
+	} `koanf:"kafka"`
+}
+
+// TestWithKeyDelimiter tests that map keys containing dots survive loading and env overrides
+func TestWithKeyDelimiter(t *testing.T) {
+	t.Setenv("KAFKA__TOPICS__ORDERS.V1__PARTITIONS", "6")
+
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	yamlFile := writeTempFile(t, tmpDir, "config.yaml", `kafka:
+  topics:
+    orders.v1:
+      partitions: 3
+    payments.v2:
+      partitions: 2`)
+
+	var p config.Provenance
+	var cfg topicConfig
+	err := config.Load(&cfg,
+		config.WithLocalYAML(yamlFile),
+		config.WithKeyDelimiter("/"),
+		config.WithProvenance(&p),
+	)
+	require.NoError(t, err)
+
+	require.Len(t, cfg.Kafka.Topics, 2)
+	assert.Equal(t, 6, cfg.Kafka.Topics["orders.v1"].Partitions)
+	assert.Equal(t, 2, cfg.Kafka.Topics["payments.v2"].Partitions)
+	assert.Equal(t, config.SourceYAML, p["kafka/topics/payments.v2/partitions"].Source)
+	assert.Equal(t, "KAFKA__TOPICS__ORDERS.V1__PARTITIONS", p["kafka/topics/orders.v1/partitions"].Location)
+}
+
+// TestWithKeyDelimiterLockedKeys tests that locked key prefixes use the configured delimiter
+func TestWithKeyDelimiterLockedKeys(t *testing.T) {
+	t.Setenv("KAFKA__TOPICS__ORDERS.V1__PARTITIONS", "6")
+
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	yamlFile := writeTempFile(t, tmpDir, "config.yaml", "kafka:\n  topics:\n    orders.v1:\n      partitions: 3\n")
+
+	var cfg topicConfig
+	err := config.Load(&cfg,
+		config.WithLocalYAML(yamlFile),
+		config.WithKeyDelimiter("/"),
+		config.WithLockedKeys("kafka/topics"),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, 3, cfg.Kafka.Topics["orders.v1"].Partitions)
+}
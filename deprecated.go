@@ -0,0 +1,40 @@
+package config
+
+import (
+	"log/slog"
+	"reflect"
+
+	"github.com/knadh/koanf/v2"
+)
+
+// Deprecation describes a deprecated key that was set by a source.
+//
+// Keys are marked as deprecated with the `deprecated` struct tag, whose value is a hint
+// such as `deprecated:"use server.listen_addr"`.
+type Deprecation struct {
+	// Key is the deprecated key.
+	Key string
+	// Hint is the value of the `deprecated` tag.
+	Hint string
+}
+
+// deprecations returns deprecated keys of the target struct that are set in k.
+func deprecations(t reflect.Type, k *koanf.Koanf, tags []string) []Deprecation {
+	var found []Deprecation
+	for _, f := range structFields(t, k.Delim(), tags) {
+		hint, ok := f.field.Tag.Lookup("deprecated")
+		if !ok || !k.Exists(f.key) {
+			continue
+		}
+
+		found = append(found, Deprecation{Key: f.key, Hint: hint})
+	}
+
+	return found
+}
+
+func (o *options) warnDeprecated(found []Deprecation) {
+	for _, d := range found {
+		o.logger.Warn("deprecated config key is set", slog.String("key", d.Key), slog.String("hint", d.Hint))
+	}
+}
@@ -0,0 +1,60 @@
+package config_test
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type deprecatedConfig struct {
+	Server struct {
+		Port       int    `koanf:"port"        deprecated:"use server.listen_addr"`
+		ListenAddr string `koanf:"listen_addr"`
+	} `koanf:"server"`
+	Legacy struct {
+		Mode string `koanf:"mode"`
+	} `koanf:"legacy" deprecated:"remove the legacy section"`
+}
+
+// TestDeprecatedKeys tests reporting of deprecated keys set by a source
+func TestDeprecatedKeys(t *testing.T) {
+	t.Setenv("SERVER__PORT", "8080")
+
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	yamlFile := writeTempFile(t, tmpDir, "config.yaml", `legacy:
+  mode: old`)
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	var info config.LoadInfo
+	var cfg deprecatedConfig
+	err := config.Load(&cfg,
+		config.WithLocalYAML(yamlFile),
+		config.WithLogger(logger),
+		config.OnLoad(func(i config.LoadInfo) { info = i }),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, []config.Deprecation{
+		{Key: "server.port", Hint: "use server.listen_addr"},
+		{Key: "legacy", Hint: "remove the legacy section"},
+	}, info.Deprecated)
+	assert.Contains(t, buf.String(), `level=WARN msg="deprecated config key is set" key=server.port hint="use server.listen_addr"`)
+	assert.Equal(t, 8080, cfg.Server.Port)
+}
+
+// TestDeprecatedKeysUnset tests that unset deprecated keys are not reported
+func TestDeprecatedKeysUnset(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	var info config.LoadInfo
+	var cfg deprecatedConfig
+	require.NoError(t, config.Load(&cfg, config.OnLoad(func(i config.LoadInfo) { info = i })))
+	assert.Empty(t, info.Deprecated)
+}
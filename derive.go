@@ -0,0 +1,70 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Deriver is implemented by configuration structs, and structs nested in them, computing fields
+// from others, e.g. a DSN from host, port and user.
+//
+// Load calls Derive after unmarshaling, nested structs first, and before validation, so derived values
+// are validated too.
+type Deriver interface {
+	Derive() error
+}
+
+func deriveFields(c any, tags []string) error {
+	return deriveValue(reflect.ValueOf(c), "", tags, 0)
+}
+
+func deriveValue(v reflect.Value, key string, tags []string, depth int) error {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct || !isNested(v.Type()) || depth > maxFieldDepth {
+		return nil
+	}
+
+	t := v.Type()
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, squash := fieldKey(f, tags)
+		if name == "-" {
+			continue
+		}
+
+		path := key
+		if !squash {
+			path = strings.TrimPrefix(key+"."+name, ".")
+		}
+		if err := deriveValue(v.Field(i), path, tags, depth+1); err != nil {
+			return err
+		}
+	}
+
+	if !v.CanAddr() {
+		return nil
+	}
+	d, ok := v.Addr().Interface().(Deriver)
+	if !ok {
+		return nil
+	}
+
+	if err := d.Derive(); err != nil {
+		if key == "" {
+			return fmt.Errorf("derive: %w", err)
+		}
+		return fmt.Errorf("derive %s: %w", key, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package config_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errNoHost = errors.New("host is required")
+
+type derivedDatabase struct {
+	Host string `koanf:"host"`
+	Port int    `koanf:"port"`
+	DSN  string `koanf:"dsn"`
+}
+
+func (d *derivedDatabase) Derive() error {
+	if d.Host == "" {
+		return errNoHost
+	}
+	d.DSN = fmt.Sprintf("postgres://%s:%d", d.Host, d.Port)
+	return nil
+}
+
+type derivedConfig struct {
+	Database derivedDatabase `koanf:"database"`
+	Summary  string          `koanf:"summary"`
+}
+
+func (c *derivedConfig) Derive() error {
+	c.Summary = "db=" + c.Database.DSN
+	return nil
+}
+
+// TestDeriver tests computing fields after unmarshaling, nested structs first
+func TestDeriver(t *testing.T) {
+	var c derivedConfig
+	require.NoError(t, config.Load(&c, config.WithEnviron(map[string]string{
+		"DATABASE__HOST": "db",
+		"DATABASE__PORT": "5432",
+	})))
+	assert.Equal(t, "postgres://db:5432", c.Database.DSN)
+	assert.Equal(t, "db=postgres://db:5432", c.Summary)
+
+	c = derivedConfig{}
+	err := config.Load(&c, config.WithEnviron(nil))
+	require.ErrorIs(t, err, errNoHost)
+	assert.Contains(t, err.Error(), "derive database:")
+}
+
+// TestDeriverSchema tests that derived values are validated
+func TestDeriverSchema(t *testing.T) {
+	schema := []byte(`{"properties": {"database": {"properties": {"dsn": {"pattern": "^mysql://"}}}}}`)
+
+	var c derivedConfig
+	err := config.Load(&c, config.WithSchema(schema), config.WithEnviron(map[string]string{"DATABASE__HOST": "db"}))
+	require.ErrorIs(t, err, config.ErrSchemaViolation)
+}
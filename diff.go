@@ -0,0 +1,104 @@
+package config
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	kmaps "github.com/knadh/koanf/maps"
+)
+
+// Change describes a single key that differs between two configurations.
+type Change struct {
+	// Key is the delimited key path, e.g. "database.host".
+	Key string
+	// Old is the previous value, nil if the key was added.
+	Old any
+	// New is the new value, nil if the key was removed.
+	New any
+	// Secret reports whether the key holds a secret value; Old and New are masked in that case.
+	Secret bool
+}
+
+// Diff returns the keys that differ between two configurations, sorted by key.
+//
+// Keys are joined with the KeyDelimiter option, "." by default. Secret values, including the keys of
+// structs tagged as secret, are compared by their actual value but reported masked. If entries of an OrderedMap
+// are reordered, the map itself is reported with its keys in order as Old and New.
+func Diff[T any](from, to T, opts ...MarshalOption) ([]Change, error) {
+	o := newMarshalOptions(opts)
+	oldRaw, oldMasked, oldOrders, err := flatten(from, o.tags, o.delim)
+	if err != nil {
+		return nil, err
+	}
+	newRaw, newMasked, newOrders, err := flatten(to, o.tags, o.delim)
+	if err != nil {
+		return nil, err
+	}
+
+	union := maps.Clone(oldRaw)
+	maps.Copy(union, newRaw)
+
+	changes := []Change{}
+	for _, k := range slices.Sorted(maps.Keys(union)) {
+		oldValue, newValue := oldRaw[k], newRaw[k]
+		if reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+
+		oldMask, newMask := maskedValue(oldRaw, oldMasked, k, o.delim), maskedValue(newRaw, newMasked, k, o.delim)
+		secret := !reflect.DeepEqual(oldValue, oldMask) || !reflect.DeepEqual(newValue, newMask)
+		changes = append(changes, Change{Key: k, Old: oldMask, New: newMask, Secret: secret})
+	}
+
+	for k, oldKeys := range oldOrders {
+		if newKeys, ok := newOrders[k]; ok && reordered(oldKeys, newKeys) {
+			changes = append(changes, Change{Key: k, Old: oldKeys, New: newKeys, Secret: false})
+		}
+	}
+	slices.SortStableFunc(changes, func(a, b Change) int { return strings.Compare(a.Key, b.Key) })
+
+	return changes, nil
+}
+
+// flatten returns the flattened raw and redacted representations of a configuration struct,
+// and the key order of its OrderedMap fields.
+func flatten(c any, tags []string, delim string) (map[string]any, map[string]any, map[string][]string, error) {
+	raw, err := toOrderedMap(c, false, tags)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	masked, err := toMap(c, true, tags)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	orders := map[string][]string{}
+	keyOrders(orders, "", delim, raw)
+
+	flatRaw, _ := kmaps.Flatten(unordered(raw).(map[string]any), nil, delim)
+	flatMasked, _ := kmaps.Flatten(masked, nil, delim)
+
+	return flatRaw, flatMasked, orders, nil
+}
+
+// maskedValue returns the redacted value of the key, nil if it is not set. Structs tagged as secret
+// are redacted as a whole, so keys nested in one get the masked value of the struct.
+func maskedValue(raw, masked map[string]any, key, delim string) any {
+	if _, ok := raw[key]; !ok {
+		return nil
+	}
+
+	for {
+		if v, ok := masked[key]; ok {
+			return v
+		}
+
+		i := strings.LastIndex(key, delim)
+		if i < 0 {
+			return nil
+		}
+		key = key[:i]
+	}
+}
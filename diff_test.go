@@ -0,0 +1,88 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDiff tests structured diff between two configurations
+func TestDiff(t *testing.T) {
+	from := newDumpConfig()
+	to := newDumpConfig()
+	to.Database.Host = "db.remote"
+	to.Database.Password = "rotated"
+	to.Tags = append(to.Tags, "c")
+
+	changes, err := config.Diff(from, to)
+	require.NoError(t, err)
+
+	assert.Equal(t, []config.Change{
+		{Key: "database.host", Old: "db.local", New: "db.remote", Secret: false},
+		{Key: "database.password", Old: "******", New: "******", Secret: true},
+		{Key: "tags", Old: []any{"a", "b"}, New: []any{"a", "b", "c"}, Secret: false},
+	}, changes)
+}
+
+// TestDiffEqual tests that equal configurations produce no changes
+func TestDiffEqual(t *testing.T) {
+	changes, err := config.Diff(newDumpConfig(), newDumpConfig())
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+// TestDiffMaps tests added and removed map keys
+func TestDiffMaps(t *testing.T) {
+	var from, to TestConfig
+	from.FeatureFlags = map[string]bool{"debug": true}
+	to.FeatureFlags = map[string]bool{"new_feature": true}
+
+	changes, err := config.Diff(&from, &to)
+	require.NoError(t, err)
+
+	assert.Equal(t, []config.Change{
+		{Key: "feature_flags.debug", Old: true, New: nil, Secret: false},
+		{Key: "feature_flags.new_feature", Old: nil, New: true, Secret: false},
+	}, changes)
+}
+
+// TestDiffSecretStruct tests that keys of structs tagged as secret are reported masked
+func TestDiffSecretStruct(t *testing.T) {
+	type cred struct {
+		User string `koanf:"user"`
+		Pass string `koanf:"pass"`
+	}
+	type credConfig struct {
+		Cred cred `koanf:"cred" secret:"true"`
+	}
+
+	from := credConfig{Cred: cred{User: "alice", Pass: "one"}}
+	to := credConfig{Cred: cred{User: "bob", Pass: "one"}}
+
+	changes, err := config.Diff(from, to)
+	require.NoError(t, err)
+
+	require.Len(t, changes, 1)
+	assert.Equal(t, "cred.user", changes[0].Key)
+	assert.True(t, changes[0].Secret)
+	assert.NotNil(t, changes[0].Old)
+	assert.Equal(t, changes[0].Old, changes[0].New)
+	assert.NotContains(t, changes[0].Old, "alice")
+	assert.NotContains(t, changes[0].New, "bob")
+}
+
+// TestDiffKeyDelimiter tests that keys are joined with the given delimiter
+func TestDiffKeyDelimiter(t *testing.T) {
+	from := newDumpConfig()
+	to := newDumpConfig()
+	to.Database.Host = "db.remote"
+
+	changes, err := config.Diff(from, to, config.KeyDelimiter("/"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []config.Change{
+		{Key: "database/host", Old: "db.local", New: "db.remote", Secret: false},
+	}, changes)
+}
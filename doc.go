@@ -0,0 +1,21 @@
+// Package config loads service configuration from YAML files, `.env` files, environment variables
+// and custom sources into a struct.
+//
+// Configuration structs are annotated with struct tags:
+//
+//   - `koanf:"name"` sets the key of a field; `koanf:",squash"` embeds the keys of a struct.
+//   - `desc:"..."` describes the field. The description is written once and used by GenerateExample,
+//     GenerateEnvExample, GenerateSchema, GenerateMarkdown and RegisterFlags.
+//   - `secret:"true"` masks the value in dumps, examples and change reports.
+//   - `oneof:"a,b"` restricts the allowed values.
+//   - `deprecated:"hint"` reports the key as deprecated when a source sets it.
+//
+// WithTag maps keys with other tags, e.g. `json`; pass Tags with the same tags to Dump, Marshal, Env,
+// Diff, Hash and the generators so they render the same keys.
+//
+// Environment variables, `.env` files and systemd credentials name keys with `__` between segments,
+// e.g. DATABASES__PRIMARY__HOST sets databases.primary.host, also for entries of maps of structs.
+// Their names are lowercased, see WithPreserveKeyCase and WithKeyMapper, and then match keys set by earlier
+// sources that differ only in case: DATABASES__REPLICA__PORT overrides the port of a YAML entry `Replica`.
+// Keys of YAML files and custom sources are case-sensitive.
+package config
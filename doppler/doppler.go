@@ -0,0 +1,156 @@
+// Package doppler provides a configuration source reading secrets from Doppler.
+//
+//	config.Load(&cfg, config.WithSource(doppler.New(os.Getenv("DOPPLER_TOKEN"))))
+//
+// Secrets are named like environment variables: DATABASE__PASSWORD sets database.password.
+package doppler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-core-fx/config"
+)
+
+const (
+	// DefaultEndpoint is the base URL of the Doppler API.
+	DefaultEndpoint = "https://api.doppler.com"
+
+	keyDelimiter    = "__"
+	maxResponseSize = 4 << 20
+)
+
+// ErrUnavailable is returned if the secrets cannot be downloaded, e.g. because Doppler is unreachable
+// or rejects the token.
+var ErrUnavailable = errors.New("doppler unavailable")
+
+// Source is a config.Source downloading the secrets of a Doppler config.
+type Source struct {
+	token    string
+	project  string
+	config   string
+	endpoint string
+	client   *http.Client
+}
+
+var _ config.Source = (*Source)(nil)
+
+// Option configures a Source.
+type Option func(*Source)
+
+// WithProject selects the project. Service tokens are scoped to a config and need neither
+// WithProject nor WithConfig.
+func WithProject(project string) Option {
+	return func(s *Source) {
+		s.project = project
+	}
+}
+
+// WithConfig selects the config of the project, e.g. "prd".
+func WithConfig(name string) Option {
+	return func(s *Source) {
+		s.config = name
+	}
+}
+
+// WithEndpoint sets the base URL of the API, DefaultEndpoint by default.
+func WithEndpoint(endpoint string) Option {
+	return func(s *Source) {
+		s.endpoint = strings.TrimSuffix(endpoint, "/")
+	}
+}
+
+// WithHTTPClient sets the HTTP client, http.DefaultClient by default.
+func WithHTTPClient(client *http.Client) Option {
+	return func(s *Source) {
+		s.client = client
+	}
+}
+
+// New creates a Source authenticating with the given token.
+func New(token string, opts ...Option) *Source {
+	s := &Source{
+		token:    token,
+		project:  "",
+		config:   "",
+		endpoint: DefaultEndpoint,
+		client:   http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Name returns "doppler".
+func (s *Source) Name() string {
+	return "doppler"
+}
+
+// Load downloads the secrets.
+func (s *Source) Load(ctx context.Context) (map[string]any, error) {
+	query := url.Values{"format": []string{"json"}}
+	if s.project != "" {
+		query.Set("project", s.project)
+	}
+	if s.config != "" {
+		query.Set("config", s.config)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		s.endpoint+"/v3/configs/config/secrets/download?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+	req.Header.Set("Authorization", "Bearer "+s.token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %s", ErrUnavailable, resp.Status)
+	}
+
+	var secrets map[string]string
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&secrets); err != nil {
+		return nil, fmt.Errorf("parse secrets: %w", err)
+	}
+
+	return nest(secrets), nil
+}
+
+// nest maps secret names to nested keys, e.g. DATABASE__PASSWORD to database.password.
+func nest(secrets map[string]string) map[string]any {
+	m := map[string]any{}
+	for name, value := range secrets {
+		if strings.HasPrefix(name, "DOPPLER_") {
+			// metadata such as DOPPLER_PROJECT
+			continue
+		}
+
+		path := strings.Split(strings.ToLower(name), keyDelimiter)
+		node := m
+		for _, key := range path[:len(path)-1] {
+			child, ok := node[key].(map[string]any)
+			if !ok {
+				child = map[string]any{}
+				node[key] = child
+			}
+			node = child
+		}
+		node[path[len(path)-1]] = value
+	}
+
+	return m
+}
@@ -0,0 +1,43 @@
+package doppler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-core-fx/config"
+	"github.com/go-core-fx/config/doppler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testConfig struct {
+	Database struct {
+		Password config.Secret `koanf:"password"`
+	} `koanf:"database"`
+	APIKey string `koanf:"api_key"`
+}
+
+// TestSource tests downloading and nesting secrets
+func TestSource(t *testing.T) {
+	t.Chdir(t.TempDir())
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v3/configs/config/secrets/download", r.URL.Path)
+		assert.Equal(t, "prd", r.URL.Query().Get("config"))
+		if r.Header.Get("Authorization") != "Bearer dp.st.token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"DATABASE__PASSWORD": "s3cr3t", "API_KEY": "key", "DOPPLER_PROJECT": "app"}`))
+	}))
+	defer srv.Close()
+
+	var c testConfig
+	src := doppler.New("dp.st.token", doppler.WithEndpoint(srv.URL), doppler.WithProject("app"), doppler.WithConfig("prd"))
+	require.NoError(t, config.Load(&c, config.WithSource(src), config.WithEnviron(nil)))
+	assert.Equal(t, config.Secret("s3cr3t"), c.Database.Password)
+	assert.Equal(t, "key", c.APIKey)
+
+	src = doppler.New("invalid", doppler.WithEndpoint(srv.URL), doppler.WithConfig("prd"))
+	require.ErrorIs(t, config.Load(&c, config.WithSource(src), config.WithEnviron(nil)), doppler.ErrUnavailable)
+}
@@ -0,0 +1,223 @@
+package config
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	kmaps "github.com/knadh/koanf/maps"
+	"github.com/knadh/koanf/parsers/yaml"
+)
+
+// Format is an output format for rendered configuration.
+type Format string
+
+const (
+	FormatYAML Format = "yaml"
+	FormatJSON Format = "json"
+)
+
+const (
+	tagName = "koanf"
+	descTag = "desc"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported format")
+	ErrUnsupportedTarget = errors.New("unsupported target")
+)
+
+// Dump renders the given configuration struct in the given format.
+//
+// Values of fields of the Secret type or tagged with `secret:"true"` are masked,
+// so the output is suitable for printing at startup and attaching to bug reports.
+func Dump(c any, format Format, opts ...MarshalOption) ([]byte, error) {
+	return Marshal(c, format, append(opts, Redact())...)
+}
+
+// Flatten returns the leaf values of the given configuration struct keyed by their key paths,
+// e.g. "database.host", with secrets masked as by Dump.
+func Flatten(c any, opts ...MarshalOption) (map[string]any, error) {
+	o := newMarshalOptions(opts)
+	m, err := toMap(c, true, o.tags)
+	if err != nil {
+		return nil, err
+	}
+
+	flat, _ := kmaps.Flatten(m, nil, o.delim)
+	return flat, nil
+}
+
+func encode(m map[string]any, format Format) ([]byte, error) {
+	switch format {
+	case FormatYAML:
+		b, err := yaml.Parser().Marshal(m)
+		if err != nil {
+			return nil, fmt.Errorf("marshal yaml: %w", err)
+		}
+		return b, nil
+	case FormatJSON:
+		b, err := json.MarshalIndent(m, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("marshal json: %w", err)
+		}
+		return append(b, '\n'), nil
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+}
+
+// toMap converts a configuration struct into a nested map keyed by the first of the given tags present on each field.
+// OrderedMap fields become plain maps, see toOrderedMap.
+func toMap(c any, redact bool, tags []string) (map[string]any, error) {
+	m, err := toOrderedMap(c, redact, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	return unordered(m).(map[string]any), nil
+}
+
+// toOrderedMap is toMap keeping OrderedMap fields as orderedValues, so they are encoded in order.
+func toOrderedMap(c any, redact bool, tags []string) (map[string]any, error) {
+	v := reflect.ValueOf(c)
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return map[string]any{}, nil
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct, reflect.Map:
+		d := dumpValue(v, redact, false, tags)
+		if o, ok := d.(orderedValues); ok {
+			d = o.values // the top level of the output is a plain map
+		}
+		m, _ := d.(map[string]any)
+		return m, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedTarget, v.Type())
+	}
+}
+
+//nolint:cyclop // a single switch over kinds reads better than several helpers
+func dumpValue(v reflect.Value, redact, secret bool, tags []string) any {
+	if redact && (secret || v.Type() == reflect.TypeFor[Secret]()) {
+		return mask(v.String())
+	}
+
+	switch {
+	case v.Type() == reflect.TypeFor[Secret]():
+		return v.String()
+	case v.Type() == reflect.TypeFor[time.Duration]():
+		return time.Duration(v.Int()).String()
+	case v.Type().Implements(reflect.TypeFor[encoding.TextMarshaler]()) && v.Kind() != reflect.Pointer:
+		if b, err := v.Interface().(encoding.TextMarshaler).MarshalText(); err == nil {
+			return string(b)
+		}
+	}
+
+	if reflect.PointerTo(v.Type()).Implements(reflect.TypeFor[orderedMap]()) {
+		p := reflect.New(v.Type())
+		p.Elem().Set(v)
+		keys, values := p.Interface().(orderedMap).orderedEntries()
+		m := make(map[string]any, len(values))
+		for key, val := range values {
+			m[key] = dumpValue(reflect.ValueOf(val), redact, secret, tags)
+		}
+		return orderedValues{keys: keys, values: m}
+	}
+
+	if reflect.PointerTo(v.Type()).Implements(reflect.TypeFor[optional]()) {
+		p := reflect.New(v.Type())
+		p.Elem().Set(v)
+		val, set := p.Interface().(optional).optionalValue()
+		if !set || val == nil {
+			return nil
+		}
+		return dumpValue(reflect.ValueOf(val), redact, secret, tags)
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return dumpValue(v.Elem(), redact, secret, tags)
+	case reflect.Struct:
+		m := map[string]any{}
+		dumpStruct(m, v, redact, tags)
+		return m
+	case reflect.Map:
+		m := make(map[string]any, v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			m[fmt.Sprint(iter.Key().Interface())] = dumpValue(iter.Value(), redact, secret, tags)
+		}
+		return m
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		s := make([]any, v.Len())
+		for i := range v.Len() {
+			s[i] = dumpValue(v.Index(i), redact, secret, tags)
+		}
+		return s
+	default:
+		return v.Interface()
+	}
+}
+
+func dumpStruct(m map[string]any, v reflect.Value, redact bool, tags []string) {
+	t := v.Type()
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, squash := fieldKey(f, tags)
+		if name == "-" {
+			continue
+		}
+
+		fv := v.Field(i)
+		if squash && fv.Kind() == reflect.Struct {
+			dumpStruct(m, fv, redact, tags)
+			continue
+		}
+
+		m[name] = dumpValue(fv, redact, f.Tag.Get("secret") == "true", tags)
+	}
+}
+
+// fieldKey returns the configuration key of a struct field and whether it is squashed into its parent.
+// The first of the given tags present on the field is used. Embedded structs are always squashed.
+func fieldKey(f reflect.StructField, tags []string) (string, bool) {
+	var tag string
+	for _, name := range tags {
+		if v, ok := f.Tag.Lookup(name); ok {
+			tag = v
+			break
+		}
+	}
+
+	name, opts, _ := strings.Cut(tag, ",")
+	squash := f.Anonymous && f.Type.Kind() == reflect.Struct
+	for _, opt := range strings.Split(opts, ",") {
+		if opt == "squash" {
+			squash = true
+		}
+	}
+
+	if name == "" {
+		name = strings.ToLower(f.Name)
+	}
+
+	return name, squash
+}
@@ -0,0 +1,93 @@
+package config_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type dumpConfig struct {
+	Database struct {
+		Host     string        `koanf:"host"`
+		Password config.Secret `koanf:"password"`
+		Token    string        `koanf:"token"    secret:"true"`
+	} `koanf:"database"`
+	Timeout time.Duration `koanf:"timeout"`
+	Tags    []string      `koanf:"tags"`
+}
+
+func newDumpConfig() dumpConfig {
+	var cfg dumpConfig
+	cfg.Database.Host = "db.local"
+	cfg.Database.Password = "s3cr3t"
+	cfg.Database.Token = "t0k3n"
+	cfg.Timeout = 30 * time.Second
+	cfg.Tags = []string{"a", "b"}
+	return cfg
+}
+
+// TestDumpJSON tests rendering the configuration as JSON with secrets masked
+func TestDumpJSON(t *testing.T) {
+	out, err := config.Dump(newDumpConfig(), config.FormatJSON)
+	require.NoError(t, err)
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(out, &got))
+
+	assert.Equal(t, map[string]any{
+		"database": map[string]any{
+			"host":     "db.local",
+			"password": "******",
+			"token":    "******",
+		},
+		"timeout": "30s",
+		"tags":    []any{"a", "b"},
+	}, got)
+}
+
+// TestDumpYAML tests rendering the configuration as YAML with secrets masked
+func TestDumpYAML(t *testing.T) {
+	cfg := newDumpConfig()
+	out, err := config.Dump(&cfg, config.FormatYAML)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(out), "host: db.local")
+	assert.Contains(t, string(out), "timeout: 30s")
+	assert.NotContains(t, string(out), "s3cr3t")
+	assert.NotContains(t, string(out), "t0k3n")
+}
+
+// TestFlatten tests flattening the configuration with secrets masked and the load options' delimiter
+func TestFlatten(t *testing.T) {
+	flat, err := config.Flatten(newDumpConfig(), config.MarshalOptionsFor(config.WithKeyDelimiter("/"))...)
+	require.NoError(t, err)
+
+	assert.Equal(t, "db.local", flat["database/host"])
+	assert.Equal(t, "******", flat["database/password"])
+	assert.Equal(t, "******", flat["database/token"])
+	assert.Equal(t, "30s", flat["timeout"])
+}
+
+// TestDumpErrors tests Dump error handling
+func TestDumpErrors(t *testing.T) {
+	_, err := config.Dump(newDumpConfig(), config.Format("toml"))
+	require.ErrorIs(t, err, config.ErrUnsupportedFormat)
+
+	_, err = config.Dump(42, config.FormatJSON)
+	require.ErrorIs(t, err, config.ErrUnsupportedTarget)
+}
+
+// TestSecretFormatting tests that secrets are masked when formatted
+func TestSecretFormatting(t *testing.T) {
+	s := config.Secret("s3cr3t")
+
+	assert.Equal(t, "s3cr3t", s.Value())
+	assert.Equal(t, "******", fmt.Sprint(s))
+	assert.Equal(t, "******", fmt.Sprintf("%#v", s))
+	assert.Empty(t, config.Secret("").String())
+}
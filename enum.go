@@ -0,0 +1,112 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+var ErrInvalidEnum = errors.New("invalid value")
+
+// Enum is implemented by types that accept only a fixed set of values, e.g.
+//
+//	type LogFormat string
+//
+//	func (LogFormat) Values() []string { return []string{"json", "text"} }
+//
+// Fields of such types are validated on Load. Alternatively, tag a field with
+// `oneof:"json,text"`. Empty values are treated as unset and are not validated.
+type Enum interface {
+	Values() []string
+}
+
+// validateEnums checks all Enum fields and fields tagged with `oneof` of the given struct.
+func validateEnums(c any, tags []string) error {
+	return validateEnumValue(reflect.ValueOf(c), "", nil, tags)
+}
+
+//nolint:cyclop // a single switch over kinds reads better than several helpers
+func validateEnumValue(v reflect.Value, key string, allowed, tags []string) error {
+	if !v.IsValid() {
+		return nil
+	}
+
+	if e, ok := v.Interface().(Enum); ok && allowed == nil && v.Kind() != reflect.Pointer {
+		allowed = e.Values()
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return validateEnumValue(v.Elem(), key, allowed, tags)
+	case reflect.Struct:
+		if allowed != nil || !isNested(v.Type()) {
+			return checkEnum(v, key, allowed)
+		}
+		return validateEnumStruct(v, key, tags)
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			if err := validateEnumValue(v.Index(i), fmt.Sprintf("%s[%d]", key, i), allowed, tags); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		for iter := v.MapRange(); iter.Next(); {
+			if err := validateEnumValue(iter.Value(), key+"."+fmt.Sprint(iter.Key().Interface()), allowed, tags); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return checkEnum(v, key, allowed)
+	}
+}
+
+func validateEnumStruct(v reflect.Value, prefix string, tags []string) error {
+	t := v.Type()
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, squash := fieldKey(f, tags)
+		if name == "-" {
+			continue
+		}
+
+		key := prefix
+		if !squash {
+			key = strings.TrimPrefix(prefix+"."+name, ".")
+		}
+
+		var allowed []string
+		if tag, ok := f.Tag.Lookup("oneof"); ok {
+			allowed = strings.Split(tag, ",")
+		}
+
+		if err := validateEnumValue(v.Field(i), key, allowed, tags); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checkEnum(v reflect.Value, key string, allowed []string) error {
+	if allowed == nil {
+		return nil
+	}
+
+	s := fmt.Sprint(v.Interface())
+	if s == "" || slices.Contains(allowed, s) {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s: %q is not one of %q", ErrInvalidEnum, key, s, allowed)
+}
@@ -0,0 +1,64 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type logFormat string
+
+func (logFormat) Values() []string { return []string{"json", "text"} }
+
+type enumConfig struct {
+	Log struct {
+		Format logFormat `koanf:"format"`
+		Level  string    `koanf:"level"  oneof:"debug,info,warn,error"`
+	} `koanf:"log"`
+	Outputs []logFormat `koanf:"outputs"`
+}
+
+// TestEnum tests validation of allowed values
+func TestEnum(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("LOG__FORMAT", "json")
+	t.Setenv("LOG__LEVEL", "warn")
+	t.Setenv("OUTPUTS", `["json","text"]`)
+
+	var cfg enumConfig
+	require.NoError(t, config.Load(&cfg))
+	assert.Equal(t, logFormat("json"), cfg.Log.Format)
+	assert.Equal(t, "warn", cfg.Log.Level)
+}
+
+// TestEnumUnset tests that unset enum values are not validated
+func TestEnumUnset(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	var cfg enumConfig
+	require.NoError(t, config.Load(&cfg))
+}
+
+// TestEnumInvalid tests that invalid values are rejected with the allowed set
+func TestEnumInvalid(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	tests := []struct {
+		env, value, message string
+	}{
+		{"LOG__FORMAT", "xml", `log.format: "xml" is not one of ["json" "text"]`},
+		{"LOG__LEVEL", "trace", `log.level: "trace" is not one of ["debug" "info" "warn" "error"]`},
+		{"OUTPUTS", `["json","yaml"]`, `outputs[1]: "yaml" is not one of ["json" "text"]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			t.Setenv(tt.env, tt.value)
+			var cfg enumConfig
+			err := config.Load(&cfg)
+			require.ErrorIs(t, err, config.ErrInvalidEnum)
+			assert.ErrorContains(t, err, tt.message)
+		})
+	}
+}
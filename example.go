@@ -0,0 +1,237 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	kmaps "github.com/knadh/koanf/maps"
+	"go.yaml.in/yaml/v3"
+)
+
+const exampleIndent = 2
+
+// Defaulter is implemented by configuration structs that set their own default values.
+//
+// Load calls SetDefaults before applying any source, and GenerateExample uses the defaults as example values.
+type Defaulter interface {
+	SetDefaults()
+}
+
+// GenerateExample returns an example configuration for the struct T in the given format,
+// suitable for shipping as config.example.yaml.
+//
+// Values are the defaults set by Defaulter, or zero values. In YAML, every key is preceded by a comment
+// with the description from the `desc` tag and the allowed values of enum fields.
+// Secrets are masked.
+func GenerateExample[T any](format Format, opts ...MarshalOption) ([]byte, error) {
+	v, err := defaults[T]()
+	if err != nil {
+		return nil, err
+	}
+
+	tags := newMarshalOptions(opts).tags
+	if format != FormatYAML {
+		m, _ := dumpValue(v, true, false, tags).(map[string]any)
+		return encode(m, format)
+	}
+
+	node := newNode(yaml.MappingNode, "!!map", "")
+	if err := exampleStruct(node, v, tags); err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(exampleIndent)
+	if err := enc.Encode(node); err != nil {
+		return nil, fmt.Errorf("marshal yaml: %w", err)
+	}
+
+	return []byte(b.String()), nil
+}
+
+// GenerateEnvExample returns a `.env.example` file for the struct T: one environment variable
+// per key, e.g. DATABASE__HOST=localhost, with the same values and comments as GenerateExample.
+func GenerateEnvExample[T any](opts ...MarshalOption) ([]byte, error) {
+	v, err := defaults[T]()
+	if err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	for _, f := range exampleFields(v, newMarshalOptions(opts).tags) {
+		if comment := exampleComment(f.field); comment != "" {
+			b.WriteString("# " + strings.ReplaceAll(comment, "\n", "\n# ") + "\n")
+		}
+		b.WriteString(f.env + "=" + envValue(f.value) + "\n")
+	}
+
+	return []byte(b.String()), nil
+}
+
+// defaults returns a new T with defaults applied.
+func defaults[T any]() (reflect.Value, error) {
+	c := new(T)
+	if d, ok := any(c).(Defaulter); ok {
+		d.SetDefaults()
+	}
+
+	v := reflect.ValueOf(c).Elem()
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("%w: %s", ErrUnsupportedTarget, v.Type())
+	}
+
+	return v, nil
+}
+
+// exampleField is a leaf key of a configuration struct with its redacted value.
+type exampleField struct {
+	key   string
+	env   string
+	value any
+	field reflect.StructField
+}
+
+func exampleFields(v reflect.Value, tags []string) []exampleField {
+	m, _ := dumpValue(v, true, false, tags).(map[string]any)
+
+	var fields []exampleField
+	for _, f := range structFields(v.Type(), defaultKeyDelimiter, tags) {
+		if isNested(indirect(f.field.Type)) {
+			continue
+		}
+
+		path := strings.Split(f.key, defaultKeyDelimiter)
+		fields = append(fields, exampleField{
+			key:   f.key,
+			env:   strings.ToUpper(strings.Join(path, envDelimiter)),
+			value: kmaps.Search(m, path),
+			field: f.field,
+		})
+	}
+
+	return fields
+}
+
+// envValue formats a value as understood by Load: lists and maps as JSON, strings quoted when needed.
+func envValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		if strings.ContainsAny(v, " \t\n#'\"\\") {
+			return strconv.Quote(v)
+		}
+		return v
+	case []any, map[string]any, orderedValues:
+		b, _ := json.Marshal(v)
+		return string(b)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func exampleStruct(node *yaml.Node, v reflect.Value, tags []string) error {
+	t := v.Type()
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, squash := fieldKey(f, tags)
+		if name == "-" {
+			continue
+		}
+
+		fv := v.Field(i)
+		if squash && fv.Kind() == reflect.Struct {
+			if err := exampleStruct(node, fv, tags); err != nil {
+				return err
+			}
+			continue
+		}
+
+		key := newNode(yaml.ScalarNode, "!!str", name)
+		key.HeadComment = exampleComment(f)
+		value, err := exampleValue(fv, f.Tag.Get("secret") == "true", tags)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		node.Content = append(node.Content, key, value)
+	}
+
+	return nil
+}
+
+func exampleValue(v reflect.Value, secret bool, tags []string) (*yaml.Node, error) {
+	if sv := indirectValue(v); sv.Kind() == reflect.Struct && isNested(sv.Type()) {
+		node := newNode(yaml.MappingNode, "!!map", "")
+		return node, exampleStruct(node, sv, tags)
+	}
+
+	val := dumpValue(v, true, secret, tags)
+	if val == nil {
+		switch indirect(v.Type()).Kind() {
+		case reflect.Slice, reflect.Array:
+			val = []any{}
+		case reflect.Map:
+			val = map[string]any{}
+		default:
+		}
+	}
+
+	node := new(yaml.Node)
+	if err := node.Encode(val); err != nil {
+		return nil, fmt.Errorf("encode: %w", err)
+	}
+
+	return node, nil
+}
+
+func newNode(kind yaml.Kind, tag, value string) *yaml.Node {
+	n := new(yaml.Node)
+	n.Kind = kind
+	n.Tag = tag
+	n.Value = value
+
+	return n
+}
+
+// exampleComment describes a field by its `desc` tag and allowed values.
+func exampleComment(f reflect.StructField) string {
+	var lines []string
+	if desc := f.Tag.Get(descTag); desc != "" {
+		lines = append(lines, desc)
+	}
+
+	allowed := f.Tag.Get("oneof")
+	if e, ok := reflect.Zero(indirect(f.Type)).Interface().(Enum); ok && allowed == "" {
+		allowed = strings.Join(e.Values(), ",")
+	}
+	if allowed != "" {
+		lines = append(lines, "one of: "+strings.ReplaceAll(allowed, ",", ", "))
+	}
+
+	if hint, ok := f.Tag.Lookup("deprecated"); ok {
+		lines = append(lines, "deprecated: "+hint)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// indirectValue dereferences pointers, allocating zero values for nil ones.
+func indirectValue(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return reflect.Zero(v.Type().Elem())
+		}
+		v = v.Elem()
+	}
+
+	return v
+}
@@ -0,0 +1,86 @@
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type exampleConfig struct {
+	Server struct {
+		Listen  string          `koanf:"listen"  desc:"Address to listen on"`
+		Timeout config.Duration `koanf:"timeout" desc:"Request timeout"`
+	} `koanf:"server"`
+	Format   logFormat     `koanf:"format"   desc:"Log format"`
+	Password config.Secret `koanf:"password"`
+	Tags     []string      `koanf:"tags"`
+}
+
+func (c *exampleConfig) SetDefaults() {
+	c.Server.Listen = ":8080"
+	c.Server.Timeout = config.Duration(30 * time.Second)
+	c.Format = "json"
+	c.Password = "changeme"
+}
+
+// TestGenerateExample tests generating a commented YAML example with defaults
+func TestGenerateExample(t *testing.T) {
+	b, err := config.GenerateExample[exampleConfig](config.FormatYAML)
+	require.NoError(t, err)
+
+	assert.Equal(t, `server:
+  # Address to listen on
+  listen: :8080
+  # Request timeout
+  timeout: 30s
+# Log format
+# one of: json, text
+format: json
+password: '******'
+tags: []
+`, string(b))
+}
+
+// TestGenerateExampleJSON tests generating a JSON example without comments
+func TestGenerateExampleJSON(t *testing.T) {
+	b, err := config.GenerateExample[exampleConfig](config.FormatJSON)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"server": {"listen": ":8080", "timeout": "30s"},
+		"format": "json",
+		"password": "******",
+		"tags": null
+	}`, string(b))
+}
+
+// TestLoadDefaulter tests that Load applies defaults before sources
+func TestLoadDefaulter(t *testing.T) {
+	t.Setenv("SERVER__LISTEN", ":9090")
+	t.Chdir(t.TempDir())
+
+	var cfg exampleConfig
+	require.NoError(t, config.Load(&cfg))
+	assert.Equal(t, ":9090", cfg.Server.Listen)
+	assert.Equal(t, config.Duration(30*time.Second), cfg.Server.Timeout)
+	assert.Equal(t, "changeme", cfg.Password.Value())
+}
+
+// TestGenerateEnvExample tests generating a .env.example file with defaults and comments
+func TestGenerateEnvExample(t *testing.T) {
+	b, err := config.GenerateEnvExample[exampleConfig]()
+	require.NoError(t, err)
+
+	assert.Equal(t, `# Address to listen on
+SERVER__LISTEN=:8080
+# Request timeout
+SERVER__TIMEOUT=30s
+# Log format
+# one of: json, text
+FORMAT=json
+PASSWORD=******
+TAGS=
+`, string(b))
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	kmaps "github.com/knadh/koanf/maps"
+)
+
+// Env flattens the configuration struct c into environment variables as read by Load, e.g.
+// DATABASE__HOST=localhost, sorted by name. Lists and maps are encoded as JSON, unset values
+// are omitted, and secrets are included unmasked.
+func Env(c any, opts ...MarshalOption) ([]string, error) {
+	m, err := toMap(c, false, newMarshalOptions(opts).tags)
+	if err != nil {
+		return nil, err
+	}
+
+	flat, _ := kmaps.Flatten(m, nil, envDelimiter)
+
+	vars := make([]string, 0, len(flat))
+	for _, key := range slices.Sorted(maps.Keys(flat)) {
+		v := flat[key]
+		if v == nil {
+			continue
+		}
+		vars = append(vars, strings.ToUpper(key)+"="+exportValue(v))
+	}
+
+	return vars, nil
+}
+
+// ExecEnv returns the environment of the current process with the variables of Env added,
+// suitable for exec.Cmd.Env. Variables of the configuration take precedence.
+func ExecEnv(c any, opts ...MarshalOption) ([]string, error) {
+	vars, err := Env(c, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(os.Environ(), vars...), nil
+}
+
+func exportValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case []any, map[string]any, orderedValues:
+		b, _ := json.Marshal(v)
+		return string(b)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,69 @@
+package config_test
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type exportConfig struct {
+	Database struct {
+		Host     string        `koanf:"host"`
+		Password config.Secret `koanf:"password"`
+	} `koanf:"database"`
+	Hosts   []string          `koanf:"hosts"`
+	Labels  map[string]string `koanf:"labels"`
+	Timeout *int              `koanf:"timeout"`
+}
+
+// TestEnv tests flattening a configuration into environment variables that load back
+func TestEnv(t *testing.T) {
+	var c exportConfig
+	c.Database.Host = "db host"
+	c.Database.Password = "s3cret"
+	c.Hosts = []string{"a", "b"}
+	c.Labels = map[string]string{}
+
+	vars, err := config.Env(&c)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"DATABASE__HOST=db host",
+		"DATABASE__PASSWORD=s3cret",
+		`HOSTS=["a","b"]`,
+		"LABELS={}",
+	}, vars)
+
+	environ := map[string]string{}
+	for _, v := range vars {
+		name, value, _ := strings.Cut(v, "=")
+		environ[name] = value
+	}
+
+	var loaded exportConfig
+	require.NoError(t, config.Load(&loaded, config.WithEnviron(environ)))
+	assert.Equal(t, c.Database, loaded.Database)
+	assert.Equal(t, c.Hosts, loaded.Hosts)
+}
+
+// TestExecEnv tests passing the configuration to a child process
+func TestExecEnv(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	var c exportConfig
+	c.Database.Host = "child"
+
+	env, err := config.ExecEnv(&c)
+	require.NoError(t, err)
+
+	cmd := exec.CommandContext(t.Context(), "sh", "-c", "echo $DATABASE__HOST")
+	cmd.Env = env
+	out, err := cmd.Output()
+	require.NoError(t, err)
+	assert.Equal(t, "child\n", string(out))
+}
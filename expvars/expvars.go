@@ -0,0 +1,101 @@
+// Package expvars publishes configuration load state via expvar.
+package expvars
+
+import (
+	"encoding/json"
+	"expvar"
+	"sync"
+	"time"
+
+	"github.com/go-core-fx/config"
+)
+
+// Vars is an expvar.Var tracking configuration loads.
+//
+// The first Load observed is the initial load, every subsequent one is treated as a reload.
+type Vars struct {
+	mu sync.Mutex
+
+	loaded         bool
+	hash           string
+	lastLoad       time.Time
+	loads          int
+	reloads        int
+	reloadFailures int
+}
+
+type snapshot struct {
+	Hash           string `json:"hash"`
+	LastLoad       string `json:"last_load"`
+	Loads          int    `json:"loads"`
+	Reloads        int    `json:"reloads"`
+	ReloadFailures int    `json:"reload_failures"`
+}
+
+// New creates a new Vars. Publish it with expvar.Publish and pass Option to Load.
+func New() *Vars {
+	return &Vars{
+		mu:             sync.Mutex{},
+		loaded:         false,
+		hash:           "",
+		lastLoad:       time.Time{},
+		loads:          0,
+		reloads:        0,
+		reloadFailures: 0,
+	}
+}
+
+// Publish creates a new Vars and publishes it under the given name, e.g. "config".
+// Like expvar.Publish, it panics if the name is already registered.
+func Publish(name string) *Vars {
+	v := New()
+	expvar.Publish(name, v)
+	return v
+}
+
+// Option returns a config option that reports every Load to v.
+func (v *Vars) Option() config.Option {
+	return config.OnLoad(v.observe)
+}
+
+// String implements expvar.Var, returning the state as a JSON object.
+func (v *Vars) String() string {
+	v.mu.Lock()
+	s := snapshot{
+		Hash:           v.hash,
+		LastLoad:       "",
+		Loads:          v.loads,
+		Reloads:        v.reloads,
+		ReloadFailures: v.reloadFailures,
+	}
+	if !v.lastLoad.IsZero() {
+		s.LastLoad = v.lastLoad.Format(time.RFC3339)
+	}
+	v.mu.Unlock()
+
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+func (v *Vars) observe(info config.LoadInfo) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	reload := v.loaded
+	v.loaded = true
+
+	if reload {
+		v.reloads++
+	}
+
+	if info.Err != nil {
+		if reload {
+			v.reloadFailures++
+		}
+		return
+	}
+
+	v.loads++
+	v.hash = info.Hash
+	v.lastLoad = time.Now()
+}
@@ -0,0 +1,63 @@
+package expvars_test
+
+import (
+	"encoding/json"
+	"expvar"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-core-fx/config"
+	"github.com/go-core-fx/config/expvars"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testConfig struct {
+	Host string `koanf:"host"`
+}
+
+type state struct {
+	Hash           string `json:"hash"`
+	LastLoad       string `json:"last_load"`
+	Loads          int    `json:"loads"`
+	Reloads        int    `json:"reloads"`
+	ReloadFailures int    `json:"reload_failures"`
+}
+
+func read(t *testing.T, v expvar.Var) state {
+	t.Helper()
+	var s state
+	require.NoError(t, json.Unmarshal([]byte(v.String()), &s))
+	return s
+}
+
+// TestVars tests load and reload counters
+func TestVars(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	path := filepath.Join(tmpDir, "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("host: a\n"), 0o644))
+
+	v := expvars.Publish("config_test")
+	assert.Same(t, v, expvar.Get("config_test"))
+	assert.Equal(t, state{}, read(t, v))
+
+	var cfg testConfig
+	require.NoError(t, config.Load(&cfg, config.WithLocalYAML(path), v.Option()))
+	s := read(t, v)
+	hash, err := config.Hash(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, hash, s.Hash)
+	assert.NotEmpty(t, s.LastLoad)
+	assert.Equal(t, 1, s.Loads)
+	assert.Equal(t, 0, s.Reloads)
+
+	require.NoError(t, os.WriteFile(path, []byte("host: [\n"), 0o644))
+	require.Error(t, config.Load(&cfg, config.WithLocalYAML(path), v.Option()))
+	s = read(t, v)
+	assert.Equal(t, hash, s.Hash)
+	assert.Equal(t, 1, s.Loads)
+	assert.Equal(t, 1, s.Reloads)
+	assert.Equal(t, 1, s.ReloadFailures)
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"encoding"
+	"reflect"
+)
+
+// field is a leaf or nested struct field of a configuration struct, addressed by its key path.
+type field struct {
+	key   string
+	field reflect.StructField
+}
+
+// structFields returns all fields of the given configuration struct type in declaration order.
+//
+// Nested structs are descended into; their own entry precedes their fields.
+// Maps, slices and types with custom text unmarshaling are treated as leaves.
+func structFields(t reflect.Type, delim string, tags []string) []field {
+	return appendFields(nil, indirect(t), "", keyFormat{delim: delim, tags: tags}, 0)
+}
+
+const maxFieldDepth = 32
+
+// keyFormat describes how struct fields map to key paths.
+type keyFormat struct {
+	delim string
+	tags  []string
+}
+
+func appendFields(fields []field, t reflect.Type, prefix string, kf keyFormat, depth int) []field {
+	if t.Kind() != reflect.Struct || depth > maxFieldDepth {
+		return fields
+	}
+
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, squash := fieldKey(f, kf.tags)
+		if name == "-" {
+			continue
+		}
+
+		ft := indirect(f.Type)
+		if squash {
+			fields = appendFields(fields, ft, prefix, kf, depth+1)
+			continue
+		}
+
+		key := prefix + name
+		fields = append(fields, field{key: key, field: f})
+		if isNested(ft) {
+			fields = appendFields(fields, ft, key+kf.delim, kf, depth+1)
+		}
+	}
+
+	return fields
+}
+
+// isNested reports whether the type is a struct whose fields map to nested keys.
+func isNested(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+
+	p := reflect.PointerTo(t)
+	return !p.Implements(reflect.TypeFor[encoding.TextUnmarshaler]()) && !p.Implements(reflect.TypeFor[optional]()) &&
+		!p.Implements(reflect.TypeFor[orderedMap]())
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return t
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// FileContents is a configuration value holding a file path that is replaced by
+// the contents of the file when the configuration is loaded.
+//
+// It covers the common "point at a token or key file" pattern. The contents are
+// not exposed by String or when marshaled; both return the path instead.
+type FileContents struct {
+	path string
+	data []byte
+}
+
+// Path returns the path of the file.
+func (f FileContents) Path() string {
+	return f.path
+}
+
+// Bytes returns the raw contents of the file.
+func (f FileContents) Bytes() []byte {
+	return f.data
+}
+
+// Text returns the contents of the file with a trailing newline trimmed.
+func (f FileContents) Text() string {
+	s := string(f.data)
+	s = strings.TrimSuffix(s, "\n")
+	return strings.TrimSuffix(s, "\r")
+}
+
+// IsZero reports whether the value is not set.
+func (f FileContents) IsZero() bool {
+	return f.path == ""
+}
+
+// String returns the path of the file.
+func (f FileContents) String() string {
+	return f.path
+}
+
+// MarshalText implements encoding.TextMarshaler. It returns the path of the file.
+func (f FileContents) MarshalText() ([]byte, error) {
+	return []byte(f.path), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It reads the file at the given path.
+func (f *FileContents) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*f = FileContents{}
+		return nil
+	}
+
+	path := string(text)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read file: %w", err)
+	}
+
+	*f = FileContents{path: path, data: data}
+	return nil
+}
@@ -0,0 +1,45 @@
+package config_test
+
+import (
+	"io/fs"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fileConfig struct {
+	Token config.FileContents `koanf:"token"`
+}
+
+// TestFileContents tests loading file contents from a path
+func TestFileContents(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	tokenFile := writeTempFile(t, tmpDir, "token", "t0k3n\n")
+	t.Setenv("TOKEN", tokenFile)
+
+	var cfg fileConfig
+	require.NoError(t, config.Load(&cfg))
+
+	assert.Equal(t, tokenFile, cfg.Token.Path())
+	assert.Equal(t, []byte("t0k3n\n"), cfg.Token.Bytes())
+	assert.Equal(t, "t0k3n", cfg.Token.Text())
+	assert.Equal(t, tokenFile, cfg.Token.String())
+
+	out, err := config.Dump(cfg, config.FormatJSON)
+	require.NoError(t, err)
+	assert.NotContains(t, string(out), "t0k3n")
+}
+
+// TestFileContentsMissing tests that a missing file fails at load
+func TestFileContentsMissing(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	t.Setenv("TOKEN", filepath.Join(tmpDir, "missing"))
+
+	var cfg fileConfig
+	require.ErrorIs(t, config.Load(&cfg), fs.ErrNotExist)
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/knadh/koanf/providers/file"
+	"github.com/knadh/koanf/v2"
+)
+
+var errRead = errors.New("read is not supported")
+
+// FileCache shares the contents of configuration files between Load calls, so services loading
+// several sub-configurations read the YAML and `.env` files once per process.
+//
+// Files are checked for changes by size and modification time on every Load and re-read if changed.
+type FileCache struct {
+	mu    sync.Mutex
+	files map[string]cachedFile
+}
+
+type cachedFile struct {
+	size    int64
+	modTime time.Time
+	data    []byte
+}
+
+// NewFileCache creates an empty FileCache. Pass it to every Load with WithFileCache.
+func NewFileCache() *FileCache {
+	return &FileCache{
+		mu:    sync.Mutex{},
+		files: map[string]cachedFile{},
+	}
+}
+
+// read returns the contents of the file at path, from the cache if unchanged.
+func (c *FileCache) read(path string) ([]byte, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("stat %s: %w", path, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if f, ok := c.files[path]; ok && f.size == info.Size() && f.modTime.Equal(info.ModTime()) {
+		return f.data, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	c.files[path] = cachedFile{size: info.Size(), modTime: info.ModTime(), data: data}
+
+	return data, nil
+}
+
+// fileProvider returns a provider reading the file at path from the file system set with WithFS, if any,
+// or through the file cache, if any. Compressed files are decompressed.
+func (o *options) fileProvider(path string) koanf.Provider {
+	var p koanf.Provider
+	switch {
+	case o.fsys != nil:
+		p = fsProvider{fsys: o.fsys, path: path}
+	case o.fileCache != nil:
+		p = cachedFileProvider{path: path, cache: o.fileCache}
+	default:
+		p = file.Provider(path)
+	}
+
+	return decompressing(p, path)
+}
+
+type cachedFileProvider struct {
+	path  string
+	cache *FileCache
+}
+
+func (p cachedFileProvider) ReadBytes() ([]byte, error) {
+	return p.cache.read(p.path)
+}
+
+func (p cachedFileProvider) Read() (map[string]any, error) {
+	return nil, errRead
+}
+
+type fsProvider struct {
+	fsys fs.FS
+	path string
+}
+
+func (p fsProvider) ReadBytes() ([]byte, error) {
+	return fs.ReadFile(p.fsys, p.path) //nolint:wrapcheck // passed through to koanf
+}
+
+func (p fsProvider) Read() (map[string]any, error) {
+	return nil, errRead
+}
@@ -0,0 +1,49 @@
+package config_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWithFileCache tests that cached files are reused until they change
+func TestWithFileCache(t *testing.T) {
+	tmpDir := t.TempDir()
+	withDotEnv(t, tmpDir, "DATABASE__USERNAME=admin")
+	yamlFile := writeTempFile(t, tmpDir, "config.yaml", "database:\n  host: first\n")
+
+	cache := config.NewFileCache()
+	var cfg TestConfig
+	require.NoError(t, config.Load(&cfg, config.WithLocalYAML(yamlFile), config.WithFileCache(cache)))
+	assert.Equal(t, "first", cfg.Database.Host)
+	assert.Equal(t, "admin", cfg.Database.Username)
+
+	// unchanged size and modification time: the cached contents are used
+	info, err := os.Stat(yamlFile)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(yamlFile, []byte("database:\n  host: other\n"), 0o644))
+	require.NoError(t, os.Chtimes(yamlFile, info.ModTime(), info.ModTime()))
+
+	cfg = TestConfig{}
+	require.NoError(t, config.Load(&cfg, config.WithLocalYAML(yamlFile), config.WithFileCache(cache)))
+	assert.Equal(t, "first", cfg.Database.Host)
+	assert.Equal(t, "admin", cfg.Database.Username)
+
+	// changed: the file is read again
+	require.NoError(t, os.Chtimes(yamlFile, info.ModTime().Add(time.Second), info.ModTime().Add(time.Second)))
+	cfg = TestConfig{}
+	require.NoError(t, config.Load(&cfg, config.WithLocalYAML(yamlFile), config.WithFileCache(cache)))
+	assert.Equal(t, "other", cfg.Database.Host)
+}
+
+// TestWithFileCacheMissingFile tests that missing files are still skipped
+func TestWithFileCacheMissingFile(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	var cfg TestConfig
+	require.NoError(t, config.Load(&cfg, config.WithLocalYAML("missing.yaml"), config.WithFileCache(config.NewFileCache())))
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/knadh/koanf/v2"
+)
+
+// RegisterFlags registers a flag for every key of the configuration struct T on fs, named by the key path,
+// e.g. -database.host, with the `desc` tag as usage and the default value shown. Boolean fields are
+// boolean flags.
+//
+// Pass the returned option to Load after fs.Parse: flags set on the command line override all sources
+// but runtime overrides. Values are parsed like environment variables, lists and maps as JSON.
+func RegisterFlags[T any](fs *flag.FlagSet, opts ...MarshalOption) Option {
+	v, err := defaults[T]()
+	if err != nil {
+		return func(o *options) {
+			o.flagSets = append(o.flagSets, func(*koanf.Koanf, string) error { return err })
+		}
+	}
+
+	values := map[string]*flagValue{}
+	for _, f := range exampleFields(v, newMarshalOptions(opts).tags) {
+		fv := &flagValue{value: "", isBool: indirect(f.field.Type).Kind() == reflect.Bool}
+		if f.value != nil {
+			fv.value = exportValue(f.value)
+		}
+		fs.Var(fv, f.key, exampleComment(f.field))
+		values[f.key] = fv
+	}
+
+	return func(o *options) {
+		o.flagSets = append(o.flagSets, func(k *koanf.Koanf, delim string) error {
+			return loadFlags(k, fs, values, delim)
+		})
+	}
+}
+
+func loadFlags(k *koanf.Koanf, fs *flag.FlagSet, values map[string]*flagValue, delim string) error {
+	var err error
+	transform := envTransform(func(s string) string { return s })
+	fs.Visit(func(f *flag.Flag) {
+		if _, ok := values[f.Name]; !ok || err != nil {
+			return
+		}
+
+		key := strings.ReplaceAll(f.Name, defaultKeyDelimiter, delim)
+		_, value := transform(key, f.Value.String())
+		err = k.Set(key, value)
+	})
+	if err != nil {
+		return fmt.Errorf("load flags: %w", err)
+	}
+
+	return nil
+}
+
+// flagValue is a flag.Value keeping the raw string passed on the command line.
+type flagValue struct {
+	value  string
+	isBool bool
+}
+
+func (v *flagValue) String() string {
+	if v == nil {
+		return ""
+	}
+	return v.value
+}
+
+func (v *flagValue) Set(s string) error {
+	v.value = s
+	return nil
+}
+
+func (v *flagValue) IsBoolFlag() bool {
+	return v.isBool
+}
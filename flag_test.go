@@ -0,0 +1,47 @@
+package config_test
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type flagConfig struct {
+	Database struct {
+		Host string `koanf:"host" desc:"database host"`
+		Port int    `koanf:"port"`
+	} `koanf:"database"`
+	Debug bool     `koanf:"debug"`
+	Hosts []string `koanf:"hosts"`
+}
+
+func (c *flagConfig) SetDefaults() {
+	c.Database.Port = 5432
+}
+
+// TestRegisterFlags tests loading struct fields from command-line flags
+func TestRegisterFlags(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	opt := config.RegisterFlags[flagConfig](fs)
+	require.NoError(t, fs.Parse([]string{"-database.host", "cli", "-debug", "-hosts", `["a","b"]`}))
+
+	var p config.Provenance
+	var c flagConfig
+	require.NoError(t, config.Load(&c, opt, config.WithProvenance(&p),
+		config.WithEnviron(map[string]string{"DATABASE__HOST": "env", "DATABASE__PORT": "6432"})))
+	assert.Equal(t, "cli", c.Database.Host)
+	assert.Equal(t, 6432, c.Database.Port)
+	assert.True(t, c.Debug)
+	assert.Equal(t, []string{"a", "b"}, c.Hosts)
+	assert.Equal(t, config.SourceFlags, p["database.host"].Source)
+
+	var usage bytes.Buffer
+	fs.SetOutput(&usage)
+	fs.PrintDefaults()
+	assert.Contains(t, usage.String(), "-database.host value\n    \tdatabase host")
+	assert.Contains(t, usage.String(), "(default 5432)")
+}
@@ -0,0 +1,110 @@
+// Package flagbridge maps the flags of a feature-flag service, such as LaunchDarkly or Firebase Remote Config,
+// into the feature_flags section of the configuration.
+//
+// The package does not depend on any vendor SDK: wrap the client in a Provider, and in a Notifier to reload
+// the configuration when flags change.
+//
+//	src := flagbridge.New("launchdarkly", provider)
+//	w, err := config.NewWatcher[Config](ctx, config.WithSource(src))
+//	go src.Watch(ctx, w.Reload)
+package flagbridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-core-fx/config"
+)
+
+// DefaultKey is the configuration key of the flags section.
+const DefaultKey = "feature_flags"
+
+// ErrWatchNotSupported is returned by Watch if the provider does not implement Notifier.
+var ErrWatchNotSupported = errors.New("provider does not report changes")
+
+// Provider returns the current values of all flags, e.g. booleans or strings.
+type Provider interface {
+	Flags(ctx context.Context) (map[string]any, error)
+}
+
+// Notifier is implemented by providers reporting flag changes. The channel receives a value
+// after flags changed and is closed when ctx is done.
+type Notifier interface {
+	Changes(ctx context.Context) <-chan struct{}
+}
+
+// Source is a config.Source loading flags from a Provider.
+type Source struct {
+	name     string
+	key      string
+	provider Provider
+}
+
+var _ config.Source = (*Source)(nil)
+
+// Option configures a Source.
+type Option func(*Source)
+
+// WithKey sets the configuration key of the flags section, DefaultKey by default.
+func WithKey(key string) Option {
+	return func(s *Source) {
+		s.key = key
+	}
+}
+
+// New creates a Source named name loading the flags of p.
+func New(name string, p Provider, opts ...Option) *Source {
+	s := &Source{name: name, key: DefaultKey, provider: p}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Name returns the name of the source.
+func (s *Source) Name() string {
+	return s.name
+}
+
+// Load returns the flags under the flags key. Dots in flag names are replaced by underscores,
+// since dots separate configuration keys.
+func (s *Source) Load(ctx context.Context) (map[string]any, error) {
+	flags, err := s.provider.Flags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get flags: %w", err)
+	}
+
+	m := make(map[string]any, len(flags))
+	for name, value := range flags {
+		m[strings.ReplaceAll(name, ".", "_")] = value
+	}
+
+	return map[string]any{s.key: m}, nil
+}
+
+// Watch calls reload, e.g. Watcher.Reload, whenever the provider reports changed flags, until ctx is done.
+// Reload errors do not stop watching; observe them with config.OnLoad.
+//
+// It returns ErrWatchNotSupported if the provider does not implement Notifier.
+func (s *Source) Watch(ctx context.Context, reload func(context.Context) error) error {
+	n, ok := s.provider.(Notifier)
+	if !ok {
+		return ErrWatchNotSupported
+	}
+
+	changes := n.Changes(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case _, ok := <-changes:
+			if !ok {
+				return nil
+			}
+			_ = reload(ctx)
+		}
+	}
+}
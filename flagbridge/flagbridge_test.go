@@ -0,0 +1,77 @@
+package flagbridge_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-core-fx/config"
+	"github.com/go-core-fx/config/flagbridge"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testConfig struct {
+	FeatureFlags map[string]bool `koanf:"feature_flags"`
+}
+
+// fakeProvider serves flags and reports changes made with set.
+type fakeProvider struct {
+	mu      sync.Mutex
+	flags   map[string]any
+	changes chan struct{}
+}
+
+func (p *fakeProvider) Flags(context.Context) (map[string]any, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.flags, nil
+}
+
+func (p *fakeProvider) Changes(context.Context) <-chan struct{} {
+	return p.changes
+}
+
+func (p *fakeProvider) set(name string, value any) {
+	p.mu.Lock()
+	p.flags = map[string]any{name: value}
+	p.mu.Unlock()
+	p.changes <- struct{}{}
+}
+
+// TestSource tests loading flags and reloading the watcher on changes
+func TestSource(t *testing.T) {
+	t.Chdir(t.TempDir())
+	p := &fakeProvider{mu: sync.Mutex{}, flags: map[string]any{"checkout.v2": true}, changes: make(chan struct{})}
+	src := flagbridge.New("flags", p)
+
+	w, err := config.NewWatcher[testConfig](t.Context(), config.WithSource(src), config.WithEnviron(nil))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"checkout_v2": true}, w.Get().FeatureFlags)
+
+	changed := make(chan struct{}, 1)
+	w.OnChange(func(config.ChangeEvent[testConfig]) { changed <- struct{}{} })
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	go func() { _ = src.Watch(ctx, w.Reload) }()
+
+	p.set("checkout.v2", false)
+	select {
+	case <-changed:
+	case <-time.After(time.Second):
+		t.Fatal("no reload")
+	}
+	assert.Equal(t, map[string]bool{"checkout_v2": false}, w.Get().FeatureFlags)
+}
+
+type staticProvider map[string]any
+
+func (p staticProvider) Flags(context.Context) (map[string]any, error) { return p, nil }
+
+// TestWatchNotSupported tests watching a provider without change notifications
+func TestWatchNotSupported(t *testing.T) {
+	src := flagbridge.New("flags", staticProvider{}, flagbridge.WithKey("flags"))
+	require.ErrorIs(t, src.Watch(t.Context(), func(context.Context) error { return nil }), flagbridge.ErrWatchNotSupported)
+}
@@ -0,0 +1,174 @@
+// Package flags provides typed access to feature flags kept in a map section of the configuration,
+// such as a FeatureFlags map[string]bool field tagged koanf:"feature_flags".
+//
+//	ff := flags.Watch(w, func(c Config) map[string]bool { return c.FeatureFlags })
+//	if ff.Bool("new_checkout", false) { ... }
+//
+// Flags follow reloads of the watcher, so lookups always see the latest values.
+package flags
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/go-core-fx/config"
+)
+
+// Flags holds the current feature flags.
+type Flags struct {
+	mu     sync.RWMutex
+	values map[string]any
+
+	cbMu     sync.Mutex
+	onChange []func(names []string)
+}
+
+// Watch returns the flags of the given section of w's configuration, updated on every reload.
+func Watch[T, V any](w *config.Watcher[T], section func(T) map[string]V) *Flags {
+	f := newFlags(map[string]any{})
+
+	// register before reading the configuration so no reload is missed, and read the latest
+	// configuration under a lock so a slower refresh cannot overwrite a newer one
+	var mu sync.Mutex
+	refresh := func() {
+		mu.Lock()
+		defer mu.Unlock()
+		f.update(convert(section(w.Get())))
+	}
+	w.OnChange(func(config.ChangeEvent[T]) { refresh() })
+	refresh()
+
+	return f
+}
+
+// Static returns fixed flags, e.g. for tests or configurations loaded without a watcher.
+func Static[V any](values map[string]V) *Flags {
+	return newFlags(convert(values))
+}
+
+func newFlags(values map[string]any) *Flags {
+	return &Flags{
+		mu:     sync.RWMutex{},
+		values: values,
+
+		cbMu:     sync.Mutex{},
+		onChange: nil,
+	}
+}
+
+func convert[V any](m map[string]V) map[string]any {
+	values := make(map[string]any, len(m))
+	for name, v := range m {
+		values[name] = v
+	}
+
+	return values
+}
+
+// Bool returns the flag as a boolean. Strings are parsed with strconv.ParseBool.
+// It returns def if the flag is not set or not a boolean.
+func (f *Flags) Bool(name string, def bool) bool {
+	switch v := f.get(name).(type) {
+	case bool:
+		return v
+	case string:
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+
+	return def
+}
+
+// String returns the flag as a string, formatting other values with fmt.Sprint.
+// It returns def if the flag is not set.
+func (f *Flags) String(name, def string) string {
+	switch v := f.get(name).(type) {
+	case nil:
+		return def
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Int returns the flag as an integer. Strings are parsed with strconv.Atoi.
+// It returns def if the flag is not set or not an integer.
+func (f *Flags) Int(name string, def int) int {
+	switch v := f.get(name).(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float64:
+		if v == float64(int(v)) {
+			return int(v)
+		}
+	case string:
+		if i, err := strconv.Atoi(v); err == nil {
+			return i
+		}
+	}
+
+	return def
+}
+
+// Names returns the names of all flags, sorted.
+func (f *Flags) Names() []string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return slices.Sorted(maps.Keys(f.values))
+}
+
+// OnChange registers a callback fired after a reload changed, added or removed flags.
+// It receives the sorted names of the changed flags.
+func (f *Flags) OnChange(fn func(names []string)) {
+	f.cbMu.Lock()
+	defer f.cbMu.Unlock()
+
+	f.onChange = append(f.onChange, fn)
+}
+
+func (f *Flags) get(name string) any {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.values[name]
+}
+
+func (f *Flags) update(values map[string]any) {
+	f.mu.Lock()
+	prev := f.values
+	f.values = values
+	f.mu.Unlock()
+
+	var changed []string
+	for name := range maps.Keys(prev) {
+		if v, ok := values[name]; !ok || !reflect.DeepEqual(v, prev[name]) {
+			changed = append(changed, name)
+		}
+	}
+	for name := range maps.Keys(values) {
+		if _, ok := prev[name]; !ok {
+			changed = append(changed, name)
+		}
+	}
+	if len(changed) == 0 {
+		return
+	}
+	slices.Sort(changed)
+
+	f.cbMu.Lock()
+	onChange := f.onChange
+	f.cbMu.Unlock()
+
+	for _, fn := range onChange {
+		fn(changed)
+	}
+}
@@ -0,0 +1,55 @@
+package flags_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-core-fx/config"
+	"github.com/go-core-fx/config/flags"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testConfig struct {
+	FeatureFlags map[string]bool `koanf:"feature_flags"`
+}
+
+// TestStatic tests typed lookups and defaults
+func TestStatic(t *testing.T) {
+	f := flags.Static(map[string]any{"on": true, "text": "true", "variant": "blue", "limit": 10, "ratio": 0.5})
+
+	assert.True(t, f.Bool("on", false))
+	assert.True(t, f.Bool("text", false))
+	assert.True(t, f.Bool("missing", true))
+	assert.False(t, f.Bool("variant", false))
+	assert.Equal(t, "blue", f.String("variant", "red"))
+	assert.Equal(t, "true", f.String("on", ""))
+	assert.Equal(t, "red", f.String("missing", "red"))
+	assert.Equal(t, 10, f.Int("limit", 0))
+	assert.Equal(t, 1, f.Int("ratio", 1))
+	assert.Equal(t, []string{"limit", "on", "ratio", "text", "variant"}, f.Names())
+}
+
+// TestWatch tests that flags follow watcher reloads
+func TestWatch(t *testing.T) {
+	w, err := config.NewWatcher[testConfig](t.Context(),
+		config.WithFS(fstest.MapFS{"config.yaml": {Data: []byte("feature_flags:\n  new_checkout: false\n")}}),
+		config.WithLocalYAML("config.yaml"),
+		config.WithEnviron(nil),
+	)
+	require.NoError(t, err)
+
+	f := flags.Watch(w, func(c testConfig) map[string]bool { return c.FeatureFlags })
+	assert.False(t, f.Bool("new_checkout", true))
+
+	var changed []string
+	f.OnChange(func(names []string) { changed = names })
+
+	require.NoError(t, w.Set(t.Context(), "feature_flags.new_checkout", true))
+	assert.True(t, f.Bool("new_checkout", false))
+	assert.Equal(t, []string{"new_checkout"}, changed)
+
+	require.NoError(t, w.Set(t.Context(), "feature_flags.dark_mode", true))
+	assert.Equal(t, []string{"dark_mode"}, changed)
+	assert.Equal(t, []string{"dark_mode", "new_checkout"}, f.Names())
+}
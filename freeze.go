@@ -0,0 +1,53 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrMutated = errors.New("frozen config was mutated")
+
+// Frozen holds a private copy of a loaded configuration, guarding against accidental mutation
+// of a struct shared by many goroutines.
+//
+// Get returns the configuration without copying, like a plain struct field, so concurrent readers
+// need no locks. Its maps and slices are shared and must not be modified; Verify detects when they were,
+// e.g. in tests. Copy returns a copy safe to modify.
+type Frozen[T any] struct {
+	value    T
+	snapshot T
+}
+
+// Freeze returns a Frozen holding a deep copy of c. Later changes to c do not affect it.
+func Freeze[T any](c T) *Frozen[T] {
+	return &Frozen[T]{value: Clone(c), snapshot: Clone(c)}
+}
+
+// Get returns the frozen configuration. Do not modify its maps, slices or pointed-to values.
+func (f *Frozen[T]) Get() T {
+	return f.value
+}
+
+// Copy returns a deep copy of the frozen configuration, safe to modify.
+func (f *Frozen[T]) Copy() T {
+	return Clone(f.value)
+}
+
+// Verify returns ErrMutated, naming the changed keys, if the configuration returned by Get was modified.
+func (f *Frozen[T]) Verify() error {
+	changes, err := Diff(f.snapshot, f.value)
+	if err != nil {
+		return fmt.Errorf("diff: %w", err)
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	keys := make([]string, len(changes))
+	for i, c := range changes {
+		keys[i] = c.Key
+	}
+
+	return fmt.Errorf("%w: %s", ErrMutated, strings.Join(keys, ", "))
+}
@@ -0,0 +1,48 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFreeze tests that a frozen config is isolated from the original and detects mutation
+func TestFreeze(t *testing.T) {
+	var c TestConfig
+	c.Database.Host = "db"
+	c.FeatureFlags = map[string]bool{"new_checkout": true}
+
+	f := config.Freeze(c)
+	c.FeatureFlags["new_checkout"] = false
+	c.Database.Host = "changed"
+	assert.True(t, f.Get().FeatureFlags["new_checkout"])
+	assert.Equal(t, "db", f.Get().Database.Host)
+	require.NoError(t, f.Verify())
+
+	cp := f.Copy()
+	cp.FeatureFlags["dark_mode"] = true
+	require.NoError(t, f.Verify())
+
+	f.Get().FeatureFlags["new_checkout"] = false
+	err := f.Verify()
+	require.ErrorIs(t, err, config.ErrMutated)
+	assert.Contains(t, err.Error(), "feature_flags.new_checkout")
+}
+
+// TestFreezeConcurrentReads tests reading a frozen config from many goroutines
+func TestFreezeConcurrentReads(t *testing.T) {
+	var c TestConfig
+	c.FeatureFlags = map[string]bool{"a": true}
+	f := config.Freeze(&c)
+
+	done := make(chan bool)
+	for range 4 {
+		go func() { done <- f.Get().FeatureFlags["a"] }()
+	}
+	for range 4 {
+		assert.True(t, <-done)
+	}
+	require.NoError(t, f.Verify())
+}
@@ -0,0 +1,21 @@
+package config
+
+import "go.uber.org/fx"
+
+// Replace returns an fx option that swaps the config of type T provided by the
+// application graph for the given override.
+//
+// It is intended for tests that need a specific configuration without touching
+// files or environment variables.
+func Replace[T any](override T) fx.Option {
+	return fx.Replace(override)
+}
+
+// Decorate returns an fx option that passes the config of type T provided by
+// the application graph through fn before it reaches its consumers.
+//
+// It is intended for tests that only need to adjust a few fields of the loaded
+// configuration.
+func Decorate[T any](fn func(T) T) fx.Option {
+	return fx.Decorate(fn)
+}
@@ -0,0 +1,52 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+// TestReplace tests replacing the config provided by the fx graph
+func TestReplace(t *testing.T) {
+	var override TestConfig
+	override.Database.Host = "override-host"
+
+	var got TestConfig
+	app := fxtest.New(t,
+		fx.Provide(func() TestConfig {
+			var cfg TestConfig
+			cfg.Database.Host = "graph-host"
+			return cfg
+		}),
+		config.Replace(override),
+		fx.Populate(&got),
+	)
+	app.RequireStart().RequireStop()
+
+	assert.Equal(t, "override-host", got.Database.Host)
+}
+
+// TestDecorate tests mutating the config provided by the fx graph
+func TestDecorate(t *testing.T) {
+	var got TestConfig
+	app := fxtest.New(t,
+		fx.Provide(func() TestConfig {
+			var cfg TestConfig
+			cfg.Database.Host = "graph-host"
+			cfg.Server.Port = 8080
+			return cfg
+		}),
+		config.Decorate(func(cfg TestConfig) TestConfig {
+			cfg.Server.Port = 9090
+			return cfg
+		}),
+		fx.Populate(&got),
+	)
+	app.RequireStart().RequireStop()
+
+	assert.Equal(t, "graph-host", got.Database.Host)
+	assert.Equal(t, 9090, got.Server.Port)
+}
@@ -0,0 +1,148 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	kmaps "github.com/knadh/koanf/maps"
+)
+
+const gitCacheDir = "go-core-fx-config/git"
+
+var ErrGit = errors.New("git failed")
+
+// gitMu serializes git commands on the shared cache directory.
+//
+//nolint:gochecknoglobals // sources are recreated on every Load
+var gitMu sync.Mutex
+
+// gitSource loads a YAML file, or the YAML files of a directory in lexical order, from a git repository
+// with the git command. The repository is fetched into a bare clone in the user cache directory.
+type gitSource struct {
+	url  string
+	ref  string
+	path string
+}
+
+func newGitSource(repoURL, ref, path string) *gitSource {
+	return &gitSource{url: repoURL, ref: ref, path: strings.Trim(path, "/")}
+}
+
+// Name returns the name of the source, `git:<url>#<ref>:<path>`, without credentials in the URL
+// so it can be logged.
+func (s *gitSource) Name() string {
+	repo := s.url
+	if u, err := url.Parse(s.url); err == nil && u.User != nil {
+		u.User = nil
+		repo = u.String()
+	}
+
+	return "git:" + repo + "#" + s.ref + ":" + s.path
+}
+
+func (s *gitSource) Load(ctx context.Context) (map[string]any, error) {
+	gitMu.Lock()
+	defer gitMu.Unlock()
+
+	dir, err := s.repoDir()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "HEAD")); errors.Is(err, os.ErrNotExist) {
+		if _, err := git(ctx, "", "init", "--quiet", "--bare", dir); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := git(ctx, dir, "fetch", "--quiet", "--depth", "1", "--force", "--", s.url, s.ref); err != nil {
+		return nil, err
+	}
+
+	args := []string{"ls-tree", "-r", "-z", "--name-only", "FETCH_HEAD"}
+	if s.path != "" {
+		args = append(args, "--", s.path)
+	}
+	out, err := git(ctx, dir, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	files := strings.Split(strings.TrimSuffix(string(out), "\x00"), "\x00")
+	if len(files) == 0 || files[0] == "" {
+		return nil, fmt.Errorf("%w: %s not found at %s", ErrGit, s.path, s.ref)
+	}
+	if len(files) > 1 || files[0] != s.path {
+		files = slices.DeleteFunc(files, func(f string) bool {
+			ext := path.Ext(f)
+			return ext != ".yaml" && ext != ".yml"
+		})
+	}
+	slices.Sort(files)
+
+	m := map[string]any{}
+	for _, file := range files {
+		data, err := git(ctx, dir, "show", "FETCH_HEAD:"+file)
+		if err != nil {
+			return nil, err
+		}
+
+		doc, err := Document{Data: data, Signature: nil}.Parse()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		kmaps.Merge(doc, m)
+	}
+
+	return m, nil
+}
+
+// repoDir returns the cache directory of the bare clone of the repository.
+func (s *gitSource) repoDir() (string, error) {
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		cache = os.TempDir()
+	}
+
+	sum := sha256.Sum256([]byte(s.url))
+	dir := filepath.Join(cache, gitCacheDir, hex.EncodeToString(sum[:8]))
+	if err := os.MkdirAll(dir, cacheDirMode); err != nil {
+		return "", fmt.Errorf("create git cache: %w", err)
+	}
+
+	return dir, nil
+}
+
+// git runs the git command in the repository at dir, if any, and returns its output.
+func git(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	command := args[0]
+	if dir != "" {
+		args = append([]string{"--git-dir", dir}, args...)
+	}
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	// never wait for credentials on a terminal
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%w: git %s: %w: %s", ErrGit, command, err, strings.TrimSpace(stderr.String()))
+	}
+
+	return out, nil
+}
@@ -0,0 +1,77 @@
+package config_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func gitRepo(t *testing.T, files map[string]string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	dir := t.TempDir()
+	for name, data := range files {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644))
+	}
+
+	for _, args := range [][]string{
+		{"init", "--quiet", "--initial-branch", "main"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "config"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	return "file://" + dir
+}
+
+// TestWithGit tests loading a file and a directory from a git repository
+func TestWithGit(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	repo := gitRepo(t, map[string]string{
+		"app/config.yaml":     "database:\n  host: git\n  port: 5432\n",
+		"conf.d/10-prod.yaml": "database:\n  host: prod\n",
+		"conf.d/00-base.yaml": "database:\n  host: base\n  port: 6432\n",
+		"conf.d/README.md":    "# not loaded\n",
+		"my app/config.yaml":  "server:\n  port: 8080\n",
+	})
+
+	var c TestConfig
+	require.NoError(t, config.Load(&c, config.WithGit(repo, "main", "app/config.yaml"), config.WithEnviron(nil)))
+	assert.Equal(t, "git", c.Database.Host)
+	assert.Equal(t, 5432, c.Database.Port)
+
+	c = TestConfig{}
+	require.NoError(t, config.Load(&c, config.WithGit(repo, "main", "conf.d"), config.WithEnviron(nil)))
+	assert.Equal(t, "prod", c.Database.Host)
+	assert.Equal(t, 6432, c.Database.Port)
+
+	// several git sources are told apart, and file names may contain spaces
+	var p config.Provenance
+	c = TestConfig{}
+	require.NoError(t, config.Load(&c,
+		config.WithGit(repo, "main", "app/config.yaml"),
+		config.WithGit(repo, "main", "my app"),
+		config.WithEnviron(nil),
+		config.WithProvenance(&p),
+	))
+	assert.Equal(t, 8080, c.Server.Port)
+	assert.Equal(t, "git:"+repo+"#main:app/config.yaml", p["database.host"].Source)
+	assert.Equal(t, "git:"+repo+"#main:my app", p["server.port"].Source)
+
+	require.ErrorIs(t, config.Load(&c, config.WithGit(repo, "main", "missing.yaml"), config.WithEnviron(nil)), config.ErrGit)
+	require.ErrorIs(t, config.Load(&c, config.WithGit(repo, "unknown", "app/config.yaml"), config.WithEnviron(nil)), config.ErrGit)
+}
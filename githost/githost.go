@@ -0,0 +1,197 @@
+// Package githost provides configuration sources fetching a YAML or JSON file through the
+// GitHub or GitLab API, for configuration repositories that are only reachable with a token.
+//
+//	src := githost.GitHub("acme", "config", "services/api.yaml",
+//		githost.WithRef("main"), githost.WithToken(os.Getenv("GITHUB_TOKEN")))
+//	w, err := config.NewWatcher[Config](ctx, config.WithSource(src))
+//
+// Responses are cached by ETag, so polling an unchanged file does not download it again.
+package githost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/go-core-fx/config"
+)
+
+const (
+	// GitHubEndpoint is the base URL of the GitHub API.
+	GitHubEndpoint = "https://api.github.com"
+	// GitLabEndpoint is the base URL of GitLab.com.
+	GitLabEndpoint = "https://gitlab.com"
+
+	maxFileSize = 4 << 20
+)
+
+// ErrUnavailable is returned if the file cannot be fetched, e.g. because the host is unreachable,
+// the file does not exist or is too large.
+var ErrUnavailable = errors.New("file unavailable")
+
+// Source is a config.RawSource fetching a file from a git hosting API.
+type Source struct {
+	name     string
+	endpoint string
+	ref      string
+	token    string
+	client   *http.Client
+	request  func(s *Source) string
+	auth     func(req *http.Request, token string)
+
+	mu   sync.Mutex
+	etag string
+	data []byte
+}
+
+var _ config.RawSource = (*Source)(nil)
+
+// Option configures a Source.
+type Option func(*Source)
+
+// WithRef selects the branch, tag or commit. By default, GitHub serves the default branch
+// and GitLab the main branch.
+func WithRef(ref string) Option {
+	return func(s *Source) {
+		s.ref = ref
+	}
+}
+
+// WithToken authenticates requests with the given access token.
+func WithToken(token string) Option {
+	return func(s *Source) {
+		s.token = token
+	}
+}
+
+// WithEndpoint sets the base URL of the API, e.g. for GitHub Enterprise or self-hosted GitLab.
+func WithEndpoint(endpoint string) Option {
+	return func(s *Source) {
+		s.endpoint = strings.TrimSuffix(endpoint, "/")
+	}
+}
+
+// WithHTTPClient sets the HTTP client, http.DefaultClient by default.
+func WithHTTPClient(client *http.Client) Option {
+	return func(s *Source) {
+		s.client = client
+	}
+}
+
+func newSource(name, endpoint, ref string, opts []Option) *Source {
+	s := &Source{
+		name:     name,
+		endpoint: endpoint,
+		ref:      ref,
+		token:    "",
+		client:   http.DefaultClient,
+		request:  nil,
+		auth:     nil,
+		mu:       sync.Mutex{},
+		etag:     "",
+		data:     nil,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// GitHub returns a source fetching the file at path in the repository owner/repo.
+func GitHub(owner, repo, path string, opts ...Option) *Source {
+	s := newSource("github", GitHubEndpoint, "", opts)
+	s.request = func(s *Source) string {
+		u := s.endpoint + "/repos/" + url.PathEscape(owner) + "/" + url.PathEscape(repo) + "/contents/" +
+			strings.TrimPrefix(path, "/")
+		if s.ref != "" {
+			u += "?ref=" + url.QueryEscape(s.ref)
+		}
+		return u
+	}
+	s.auth = func(req *http.Request, token string) {
+		req.Header.Set("Accept", "application/vnd.github.raw+json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+
+	return s
+}
+
+// GitLab returns a source fetching the file at path in the project, given by its ID or full path, e.g. "acme/config".
+func GitLab(project, path string, opts ...Option) *Source {
+	s := newSource("gitlab", GitLabEndpoint, "main", opts)
+	s.request = func(s *Source) string {
+		return s.endpoint + "/api/v4/projects/" + url.PathEscape(project) + "/repository/files/" +
+			url.PathEscape(strings.TrimPrefix(path, "/")) + "/raw?ref=" + url.QueryEscape(s.ref)
+	}
+	s.auth = func(req *http.Request, token string) {
+		if token != "" {
+			req.Header.Set("PRIVATE-TOKEN", token)
+		}
+	}
+
+	return s
+}
+
+// Name returns the name of the host, e.g. "github".
+func (s *Source) Name() string {
+	return s.name
+}
+
+// Load fetches and parses the file.
+func (s *Source) Load(ctx context.Context) (map[string]any, error) {
+	doc, err := s.LoadRaw(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc.Parse() //nolint:wrapcheck // errors name the problem
+}
+
+// LoadRaw fetches the file, or returns the cached copy if it is unchanged.
+func (s *Source) LoadRaw(ctx context.Context) (config.Document, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.request(s), nil)
+	if err != nil {
+		return config.Document{}, fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+	s.auth(req, s.token)
+	if s.etag != "" {
+		req.Header.Set("If-None-Match", s.etag)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return config.Document{}, fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		return config.Document{Data: s.data, Signature: nil}, nil
+	case http.StatusOK:
+	default:
+		return config.Document{}, fmt.Errorf("%w: %s", ErrUnavailable, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFileSize+1))
+	if err != nil {
+		return config.Document{}, fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+	if len(data) > maxFileSize {
+		return config.Document{}, fmt.Errorf("%w: file exceeds %d bytes", ErrUnavailable, maxFileSize)
+	}
+
+	s.etag, s.data = resp.Header.Get("ETag"), data
+
+	return config.Document{Data: data, Signature: nil}, nil
+}
@@ -0,0 +1,88 @@
+package githost_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-core-fx/config"
+	"github.com/go-core-fx/config/githost"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testConfig struct {
+	Server struct {
+		Host string `koanf:"host"`
+	} `koanf:"server"`
+}
+
+// TestGitHub tests fetching a file with a token and reusing it while the ETag matches
+func TestGitHub(t *testing.T) {
+	t.Chdir(t.TempDir())
+	downloads := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repos/acme/config/contents/services/api.yaml", r.URL.Path)
+		assert.Equal(t, "main", r.URL.Query().Get("ref"))
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		downloads++
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte("server:\n  host: github\n"))
+	}))
+	defer srv.Close()
+
+	src := githost.GitHub("acme", "config", "services/api.yaml",
+		githost.WithEndpoint(srv.URL), githost.WithRef("main"), githost.WithToken("token"))
+
+	for range 2 {
+		var c testConfig
+		require.NoError(t, config.Load(&c, config.WithSource(src), config.WithEnviron(nil)))
+		assert.Equal(t, "github", c.Server.Host)
+	}
+	assert.Equal(t, 1, downloads)
+}
+
+// TestGitLab tests fetching a raw file from a project by path
+func TestGitLab(t *testing.T) {
+	t.Chdir(t.TempDir())
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v4/projects/acme%2Fconfig/repository/files/services%2Fapi.yaml/raw", r.URL.EscapedPath())
+		assert.Equal(t, "main", r.URL.Query().Get("ref"))
+		if r.Header.Get("PRIVATE-TOKEN") != "token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"server": {"host": "gitlab"}}`))
+	}))
+	defer srv.Close()
+
+	var c testConfig
+	src := githost.GitLab("acme/config", "services/api.yaml", githost.WithEndpoint(srv.URL), githost.WithToken("token"))
+	require.NoError(t, config.Load(&c, config.WithSource(src), config.WithEnviron(nil)))
+	assert.Equal(t, "gitlab", c.Server.Host)
+
+	src = githost.GitLab("acme/config", "services/api.yaml", githost.WithEndpoint(srv.URL))
+	require.ErrorIs(t, config.Load(&c, config.WithSource(src), config.WithEnviron(nil)), githost.ErrUnavailable)
+}
+
+// TestFileTooLarge tests that files over the size limit are rejected instead of truncated
+func TestFileTooLarge(t *testing.T) {
+	t.Chdir(t.TempDir())
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("server:\n  host: github\n# "))
+		_, _ = w.Write(bytes.Repeat([]byte("x"), 4<<20))
+	}))
+	defer srv.Close()
+
+	src := githost.GitHub("acme", "config", "services/api.yaml", githost.WithEndpoint(srv.URL))
+
+	var c testConfig
+	err := config.Load(&c, config.WithSource(src), config.WithEnviron(nil))
+	require.ErrorIs(t, err, githost.ErrUnavailable)
+	assert.Contains(t, err.Error(), "exceeds")
+}
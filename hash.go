@@ -0,0 +1,28 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// Hash returns a stable hex-encoded SHA-256 digest of the given configuration struct.
+//
+// The digest depends only on the effective values, not on the sources they came from,
+// so it can be compared across instances to detect drift. Secret values are included.
+func Hash(c any, opts ...MarshalOption) (string, error) {
+	m, err := toOrderedMap(c, false, newMarshalOptions(opts).tags)
+	if err != nil {
+		return "", err
+	}
+
+	// encoding/json sorts map keys, which makes the encoding canonical; OrderedMap entries keep their order.
+	b, err := json.Marshal(m)
+	if err != nil {
+		return "", fmt.Errorf("marshal json: %w", err)
+	}
+
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
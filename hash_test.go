@@ -0,0 +1,44 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHash tests the configuration fingerprint
+func TestHash(t *testing.T) {
+	a := newDumpConfig()
+	b := newDumpConfig()
+
+	ha, err := config.Hash(a)
+	require.NoError(t, err)
+	hb, err := config.Hash(&b)
+	require.NoError(t, err)
+	assert.Len(t, ha, 64)
+	assert.Equal(t, ha, hb)
+
+	b.Database.Password = "rotated"
+	hb, err = config.Hash(b)
+	require.NoError(t, err)
+	assert.NotEqual(t, ha, hb)
+
+	_, err = config.Hash("not a struct")
+	require.ErrorIs(t, err, config.ErrUnsupportedTarget)
+}
+
+// TestLoadInfoHash tests that OnLoad receives the configuration hash
+func TestLoadInfoHash(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("DATABASE__HOST", "env-host")
+
+	var info config.LoadInfo
+	var cfg TestConfig
+	require.NoError(t, config.Load(&cfg, config.OnLoad(func(i config.LoadInfo) { info = i })))
+
+	expected, err := config.Hash(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, expected, info.Hash)
+}
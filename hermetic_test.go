@@ -0,0 +1,47 @@
+package config_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHermetic tests loading from a virtual environment and file system in parallel
+func TestHermetic(t *testing.T) {
+	fsys := fstest.MapFS{
+		"config.yaml": {Data: []byte("database:\n  host: yaml\n  port: 5432\n")},
+		".env":        {Data: []byte("DATABASE__USERNAME=dotenv\n")},
+	}
+
+	for _, host := range []string{"a", "b", "c"} {
+		t.Run(host, func(t *testing.T) {
+			t.Parallel()
+
+			var c TestConfig
+			require.NoError(t, config.Load(&c,
+				config.WithFS(fsys),
+				config.WithLocalYAML("config.yaml"),
+				config.WithEnviron(map[string]string{"DATABASE__HOST": host}),
+			))
+			assert.Equal(t, host, c.Database.Host)
+			assert.Equal(t, 5432, c.Database.Port)
+			assert.Equal(t, "dotenv", c.Database.Username)
+		})
+	}
+}
+
+// TestHermeticEmpty tests that an empty environment and file system load nothing
+func TestHermeticEmpty(t *testing.T) {
+	t.Setenv("DATABASE__HOST", "env")
+
+	var c TestConfig
+	require.NoError(t, config.Load(&c,
+		config.WithFS(fstest.MapFS{}),
+		config.WithLocalYAML("config.yaml"),
+		config.WithEnviron(nil),
+	))
+	assert.Empty(t, c.Database.Host)
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"log/slog"
+	"time"
+)
+
+// SourceInfo describes a single loaded configuration source.
+type SourceInfo struct {
+	// Name is the name of the source, e.g. SourceYAML or SourceEnv.
+	Name string
+	// Location is the file path for file-based sources.
+	Location string
+	// Keys are the keys contributed by the source.
+	Keys []string
+	// Bytes is the size of the raw data read, for file-based sources and RawSources.
+	Bytes int
+	// Duration is the time it took to load the source.
+	Duration time.Duration
+	// Err is the error loading the source, if any. Failed sources are not merged.
+	Err error
+}
+
+// LoadInfo describes a completed Load.
+type LoadInfo struct {
+	// Sources are the loaded sources in merge order.
+	Sources []SourceInfo
+	// Keys are the keys of the effective configuration.
+	Keys []string
+	// Duration is the total time it took to load the configuration.
+	Duration time.Duration
+	// Deprecated are the deprecated keys set by any source.
+	Deprecated []Deprecation
+	// Hash is a stable SHA-256 digest of the unmarshaled configuration, empty if Load failed.
+	Hash string
+	// Err is the error returned by Load, if any.
+	Err error
+}
+
+func (o *options) sourceLoaded(src SourceInfo) {
+	if src.Err != nil {
+		o.logger.Debug("config source failed",
+			slog.String("source", src.Name),
+			slog.String("path", src.Location),
+			slog.Duration("duration", src.Duration),
+			slog.String("error", src.Err.Error()),
+		)
+	} else {
+		o.logger.Debug("config source loaded",
+			slog.String("source", src.Name),
+			slog.String("path", src.Location),
+			slog.Int("keys", len(src.Keys)),
+			slog.Int("bytes", src.Bytes),
+			slog.Duration("duration", src.Duration),
+		)
+	}
+
+	for _, fn := range o.onSourceLoaded {
+		fn(src)
+	}
+}
@@ -0,0 +1,60 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHooks tests source and load callbacks
+func TestHooks(t *testing.T) {
+	t.Setenv("SERVER__PORT", "8080")
+
+	tmpDir := t.TempDir()
+	withDotEnv(t, tmpDir, `DATABASE__PORT=5433`)
+	yamlFile := writeTempFile(t, tmpDir, "config.yaml", `database:
+  host: yaml-host`)
+
+	var sources []config.SourceInfo
+	var loads []config.LoadInfo
+
+	var cfg TestConfig
+	err := config.Load(&cfg,
+		config.WithLocalYAML(yamlFile),
+		config.OnSourceLoaded(func(src config.SourceInfo) { sources = append(sources, src) }),
+		config.OnLoad(func(info config.LoadInfo) { loads = append(loads, info) }),
+	)
+	require.NoError(t, err)
+
+	require.Len(t, sources, 3)
+	assert.Equal(t, config.SourceYAML, sources[0].Name)
+	assert.Equal(t, yamlFile, sources[0].Location)
+	assert.Equal(t, []string{"database.host"}, sources[0].Keys)
+	assert.Equal(t, config.SourceDotenv, sources[1].Name)
+	assert.Equal(t, []string{"database.port"}, sources[1].Keys)
+	assert.Equal(t, config.SourceEnv, sources[2].Name)
+	assert.Contains(t, sources[2].Keys, "server.port")
+
+	require.Len(t, loads, 1)
+	require.NoError(t, loads[0].Err)
+	assert.Equal(t, sources, loads[0].Sources)
+	assert.Contains(t, loads[0].Keys, "database.host")
+	assert.Positive(t, loads[0].Duration)
+}
+
+// TestOnLoadError tests that the load callback receives the error
+func TestOnLoadError(t *testing.T) {
+	tmpDir := t.TempDir()
+	yamlFile := writeTempFile(t, tmpDir, "invalid.yaml", `invalid: yaml: content: [`)
+
+	var got error
+	var cfg TestConfig
+	err := config.Load(&cfg,
+		config.WithLocalYAML(yamlFile),
+		config.OnLoad(func(info config.LoadInfo) { got = info.Err }),
+	)
+	require.Error(t, err)
+	assert.ErrorIs(t, got, err)
+}
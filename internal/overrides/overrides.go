@@ -0,0 +1,41 @@
+// Package overrides holds process-wide configuration overrides injected by configtest.
+package overrides
+
+import (
+	"maps"
+	"sync"
+)
+
+//nolint:gochecknoglobals // overrides must reach every Load call in the test binary
+var (
+	mu     sync.RWMutex
+	values map[string]any
+)
+
+// Set adds the given values on top of the current overrides and returns a function restoring the previous ones.
+func Set(m map[string]any) func() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	prev := values
+	values = maps.Clone(prev)
+	if values == nil {
+		values = make(map[string]any, len(m))
+	}
+	maps.Copy(values, m)
+
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		values = prev
+	}
+}
+
+// Get returns a copy of the current overrides.
+func Get() map[string]any {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return maps.Clone(values)
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/knadh/koanf/v2"
+)
+
+// matchKeyCase renames keys of lk, loaded from environment-style sources, to the spelling of keys
+// already set in k that differ only in case, so DATABASES__REPLICA__HOST overrides the map entry
+// `Replica` of a YAML file instead of adding a separate `replica` entry.
+func matchKeyCase(k, lk *koanf.Koanf, delim string) (*koanf.Koanf, error) {
+	if len(k.Keys()) == 0 {
+		return lk, nil
+	}
+
+	m, changed := foldKeys(k.Raw(), lk.Raw())
+	if !changed {
+		return lk, nil
+	}
+
+	matched := koanf.New(delim)
+	if err := matched.Load(rawProvider(m), nil); err != nil {
+		return nil, fmt.Errorf("match key case: %w", err)
+	}
+
+	return matched, nil
+}
+
+func foldKeys(dst, src map[string]any) (map[string]any, bool) {
+	out := make(map[string]any, len(src))
+	changed := false
+	for key, v := range src {
+		if _, ok := dst[key]; !ok {
+			if existing, ok := findFold(dst, key); ok {
+				key = existing
+				changed = true
+			}
+		}
+
+		if sm, ok := v.(map[string]any); ok {
+			if dm, ok := dst[key].(map[string]any); ok {
+				var c bool
+				v, c = foldKeys(dm, sm)
+				changed = changed || c
+			}
+		}
+
+		out[key] = v
+	}
+
+	return out, changed
+}
+
+// findFold returns the only key of m equal to key under case folding.
+func findFold(m map[string]any, key string) (string, bool) {
+	found := ""
+	for k := range m {
+		if strings.EqualFold(k, key) {
+			if found != "" {
+				return "", false
+			}
+			found = k
+		}
+	}
+
+	return found, found != ""
+}
@@ -0,0 +1,79 @@
+package config_test
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type headersConfig struct {
+	HTTP struct {
+		Headers map[string]string `koanf:"headers"`
+	} `koanf:"http"`
+}
+
+// TestWithPreserveKeyCase tests that env map keys keep their case while struct fields still match
+func TestWithPreserveKeyCase(t *testing.T) {
+	t.Setenv("HTTP__HEADERS__Content-Type", "application/json")
+
+	tmpDir := t.TempDir()
+	withDotEnv(t, tmpDir, "HTTP__HEADERS__userAgent=cli")
+
+	var cfg headersConfig
+	require.NoError(t, config.Load(&cfg, config.WithPreserveKeyCase()))
+
+	assert.Equal(t, "application/json", cfg.HTTP.Headers["Content-Type"])
+	assert.Equal(t, "cli", cfg.HTTP.Headers["userAgent"])
+}
+
+// TestWithKeyMapper tests a custom key mapping for env keys
+func TestWithKeyMapper(t *testing.T) {
+	t.Setenv("HTTP__HEADERS__X_TRACE_ID", "abc")
+	t.Chdir(t.TempDir())
+
+	var cfg headersConfig
+	err := config.Load(&cfg, config.WithKeyMapper(func(key string) string {
+		return strings.ReplaceAll(strings.ToLower(key), "_", "-")
+	}))
+	require.NoError(t, err)
+
+	assert.Equal(t, "abc", cfg.HTTP.Headers["x-trace-id"])
+}
+
+type databasesConfig struct {
+	Databases map[string]struct {
+		Host string `koanf:"host"`
+		Port int    `koanf:"port"`
+	} `koanf:"databases"`
+}
+
+// TestMapOfStructsEnv tests overriding entries of a map of structs with env variables
+func TestMapOfStructsEnv(t *testing.T) {
+	files := fstest.MapFS{"config.yaml": {Data: []byte(`
+databases:
+  primary:
+    host: a
+    port: 5432
+  Replica:
+    host: r
+    port: 5432
+`)}}
+
+	var c databasesConfig
+	require.NoError(t, loadYAML(t, &c, files, config.WithEnviron(map[string]string{
+		"DATABASES__PRIMARY__HOST": "env",
+		"DATABASES__REPLICA__PORT": "6432",
+		"DATABASES__ANALYTICS":     `{"host": "olap", "port": 9000}`,
+	})))
+
+	require.Len(t, c.Databases, 3)
+	assert.Equal(t, "env", c.Databases["primary"].Host)
+	assert.Equal(t, 5432, c.Databases["primary"].Port)
+	assert.Equal(t, "r", c.Databases["Replica"].Host)
+	assert.Equal(t, 6432, c.Databases["Replica"].Port)
+	assert.Equal(t, "olap", c.Databases["analytics"].Host)
+}
@@ -0,0 +1,28 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/go-core-fx/config"
+	"github.com/knadh/koanf/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWithKoanf tests merging an existing koanf instance below env variables
+func TestWithKoanf(t *testing.T) {
+	k := koanf.New("/")
+	require.NoError(t, k.Set("database/host", "koanf"))
+	require.NoError(t, k.Set("database/port", 5432))
+
+	var p config.Provenance
+	var c TestConfig
+	require.NoError(t, config.Load(&c, config.WithKoanf(k), config.WithProvenance(&p),
+		config.WithEnviron(map[string]string{"DATABASE__PORT": "6432"})))
+	assert.Equal(t, "koanf", c.Database.Host)
+	assert.Equal(t, 6432, c.Database.Port)
+	assert.Equal(t, config.SourceKoanf, p["database.host"].Source)
+
+	// the instance is not modified
+	assert.Equal(t, []string{"database/host", "database/port"}, k.Keys())
+}
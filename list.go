@@ -0,0 +1,62 @@
+package config
+
+import (
+	"encoding"
+	"reflect"
+	"strings"
+
+	"github.com/go-viper/mapstructure/v2"
+)
+
+const defaultListSeparator = ","
+
+// StringList is a list of strings that can also be configured as a single separated string,
+// e.g. TAGS=a,b,c instead of TAGS=["a","b","c"].
+//
+// Items are trimmed of surrounding whitespace and empty items are dropped.
+// The separator defaults to a comma and can be changed with WithListSeparator.
+type StringList []string
+
+// UnmarshalText implements encoding.TextUnmarshaler using the default separator.
+func (l *StringList) UnmarshalText(text []byte) error {
+	*l = splitList(string(text), defaultListSeparator)
+	return nil
+}
+
+func splitList(s, sep string) StringList {
+	var items StringList
+	for _, item := range strings.Split(s, sep) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// stringListHook splits scalar strings into StringList values using the configured separator.
+func stringListHook(sep string) mapstructure.DecodeHookFuncType {
+	return func(f, t reflect.Type, data any) (any, error) {
+		if f.Kind() != reflect.String || t != reflect.TypeFor[StringList]() {
+			return data, nil
+		}
+
+		return splitList(reflect.ValueOf(data).String(), sep), nil
+	}
+}
+
+// sliceSplitHook splits scalar strings into []string, which the decoder then converts
+// into the element type of the target slice, e.g. []int.
+func sliceSplitHook(sep string) mapstructure.DecodeHookFuncType {
+	return func(f, t reflect.Type, data any) (any, error) {
+		if f.Kind() != reflect.String || t.Kind() != reflect.Slice || t.Elem().Kind() == reflect.Uint8 {
+			return data, nil
+		}
+
+		if reflect.PointerTo(t).Implements(reflect.TypeFor[encoding.TextUnmarshaler]()) {
+			return data, nil
+		}
+
+		return []string(splitList(reflect.ValueOf(data).String(), sep)), nil
+	}
+}
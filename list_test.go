@@ -0,0 +1,73 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type listConfig struct {
+	Tags config.StringList `koanf:"tags"`
+}
+
+// TestStringList tests splitting separated strings into lists
+func TestStringList(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+
+	t.Run("env", func(t *testing.T) {
+		t.Setenv("TAGS", " a, b ,,c ")
+		var cfg listConfig
+		require.NoError(t, config.Load(&cfg))
+		assert.Equal(t, config.StringList{"a", "b", "c"}, cfg.Tags)
+	})
+
+	t.Run("separator", func(t *testing.T) {
+		t.Setenv("TAGS", "a;b,c")
+		var cfg listConfig
+		require.NoError(t, config.Load(&cfg, config.WithListSeparator(";")))
+		assert.Equal(t, config.StringList{"a", "b,c"}, cfg.Tags)
+	})
+
+	t.Run("yaml list", func(t *testing.T) {
+		yamlFile := writeTempFile(t, tmpDir, "config.yaml", "tags:\n  - x\n  - y\n")
+		var cfg listConfig
+		require.NoError(t, config.Load(&cfg, config.WithLocalYAML(yamlFile)))
+		assert.Equal(t, config.StringList{"x", "y"}, cfg.Tags)
+	})
+
+	t.Run("json array", func(t *testing.T) {
+		t.Setenv("TAGS", `["x","y"]`)
+		var cfg listConfig
+		require.NoError(t, config.Load(&cfg))
+		assert.Equal(t, config.StringList{"x", "y"}, cfg.Tags)
+	})
+}
+
+// TestStringListUnmarshalText tests text unmarshaling with the default separator
+func TestStringListUnmarshalText(t *testing.T) {
+	var l config.StringList
+	require.NoError(t, l.UnmarshalText([]byte("a, b")))
+	assert.Equal(t, config.StringList{"a", "b"}, l)
+}
+
+// TestWithSplitSlices tests splitting scalar strings into arbitrary slice fields
+func TestWithSplitSlices(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("TAGS", "a, b,c")
+	t.Setenv("PORTS", "80,443")
+
+	var cfg struct {
+		Tags  []string `koanf:"tags"`
+		Ports []int    `koanf:"ports"`
+	}
+
+	require.NoError(t, config.Load(&cfg, config.WithSplitSlices()))
+	assert.Equal(t, []string{"a", "b", "c"}, cfg.Tags)
+	assert.Equal(t, []int{80, 443}, cfg.Ports)
+
+	// without the option, "80,443" is decoded as a single item, which is not an int
+	require.Error(t, config.Load(&cfg))
+}
@@ -0,0 +1,40 @@
+package config_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type databaseConfig struct {
+	Host string `koanf:"host"`
+	Port int    `koanf:"port"`
+	Mode string `koanf:"mode" oneof:"primary,replica"`
+}
+
+// TestLoadAll tests unmarshaling several targets from one load
+func TestLoadAll(t *testing.T) {
+	files := fstest.MapFS{"config.yaml": {Data: []byte("database:\n  host: db\n  port: 5432\nserver:\n  port: 80\n")}}
+	loads := 0
+	opts := []config.Option{
+		config.WithFS(files), config.WithLocalYAML("config.yaml"),
+		config.WithEnviron(map[string]string{"DATABASE__MODE": "replica"}),
+		config.OnLoad(func(config.LoadInfo) { loads++ }),
+	}
+
+	var app TestConfig
+	var db databaseConfig
+	require.NoError(t, config.LoadAll(opts, config.Target("", &app), config.Target("database", &db)))
+	assert.Equal(t, "db", app.Database.Host)
+	assert.Equal(t, 80, app.Server.Port)
+	assert.Equal(t, databaseConfig{Host: "db", Port: 5432, Mode: "replica"}, db)
+	assert.Equal(t, 1, loads)
+
+	opts = append(opts, config.WithEnviron(map[string]string{"DATABASE__MODE": "standby"}))
+	err := config.LoadAll(opts, config.Target("", &app), config.Target("database", &db))
+	require.ErrorIs(t, err, config.ErrInvalidEnum)
+	assert.Contains(t, err.Error(), "database: ")
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"log/slog"
+	"path"
+	"strings"
+
+	"github.com/knadh/koanf/v2"
+)
+
+// dropLocked removes keys matching the locked patterns from lk if an earlier source already set them,
+// and keys that would replace the parent section of such a locked key.
+func (o *options) dropLocked(k, lk *koanf.Koanf, source string) {
+	if len(o.lockedKeys) == 0 {
+		return
+	}
+
+	var locked []string
+	for _, key := range k.Keys() {
+		if isLocked(key, o.lockedKeys, k.Delim()) {
+			locked = append(locked, key)
+		}
+	}
+
+	for _, key := range lk.Keys() {
+		if !isAncestor(key, locked, k.Delim()) {
+			continue
+		}
+
+		lk.Delete(key)
+		o.logger.Warn("override of locked config key ignored", slog.String("key", key), slog.String("source", source))
+	}
+}
+
+// isLocked reports whether the key matches any of the patterns.
+// A pattern matches a key per path.Match, or if the key is nested under the pattern.
+func isLocked(key string, patterns []string, delim string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, key); ok || key == p || strings.HasPrefix(key, p+delim) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isAncestor reports whether the key is any of the keys or a parent section of one.
+func isAncestor(key string, keys []string, delim string) bool {
+	for _, k := range keys {
+		if k == key || strings.HasPrefix(k, key+delim) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,80 @@
+package config_test
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWithLockedKeys tests that locked keys cannot be overridden by later sources
+func TestWithLockedKeys(t *testing.T) {
+	t.Setenv("DATABASE__HOST", "injected-host")
+	t.Setenv("DATABASE__PASSWORD", "injected-pass")
+	t.Setenv("SERVER__PORT", "9999")
+
+	tmpDir := t.TempDir()
+	withDotEnv(t, tmpDir, `DATABASE__USERNAME=injected-user`)
+	yamlFile := writeTempFile(t, tmpDir, "config.yaml", `database:
+  host: baked-host
+  username: baked-user
+server:
+  port: 8080`)
+
+	var buf bytes.Buffer
+	var cfg TestConfig
+	err := config.Load(&cfg,
+		config.WithLocalYAML(yamlFile),
+		config.WithLockedKeys("database.*"),
+		config.WithLogger(slog.New(slog.NewTextHandler(&buf, nil))),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, "baked-host", cfg.Database.Host)
+	assert.Equal(t, "baked-user", cfg.Database.Username)
+	// not set by the trusted source, so nothing is overridden
+	assert.Equal(t, "injected-pass", cfg.Database.Password)
+	assert.Equal(t, 9999, cfg.Server.Port)
+	assert.Contains(t, buf.String(), `msg="override of locked config key ignored" key=database.host source=env`)
+}
+
+// TestWithLockedKeysPrefix tests locking a whole section by its key
+func TestWithLockedKeysPrefix(t *testing.T) {
+	t.Setenv("SERVER__PORT", "9999")
+
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	yamlFile := writeTempFile(t, tmpDir, "config.yaml", "server:\n  port: 8080\n")
+
+	var cfg TestConfig
+	require.NoError(t, config.Load(&cfg, config.WithLocalYAML(yamlFile), config.WithLockedKeys("server")))
+	assert.Equal(t, 8080, cfg.Server.Port)
+}
+
+// TestWithLockedKeysParent tests that a locked key cannot be replaced by overriding its parent section
+func TestWithLockedKeysParent(t *testing.T) {
+	type tlsConfig struct {
+		Security struct {
+			TLS any `koanf:"tls"`
+		} `koanf:"security"`
+	}
+
+	t.Setenv("SECURITY__TLS", "off")
+
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	yamlFile := writeTempFile(t, tmpDir, "config.yaml", "security:\n  tls:\n    min_version: \"1.3\"\n")
+
+	var buf bytes.Buffer
+	var cfg tlsConfig
+	require.NoError(t, config.Load(&cfg,
+		config.WithLocalYAML(yamlFile),
+		config.WithLockedKeys("security.tls.min_version"),
+		config.WithLogger(slog.New(slog.NewTextHandler(&buf, nil))),
+	))
+	assert.Equal(t, map[string]any{"min_version": "1.3"}, cfg.Security.TLS)
+	assert.Contains(t, buf.String(), `msg="override of locked config key ignored" key=security.tls source=env`)
+}
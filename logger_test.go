@@ -0,0 +1,35 @@
+package config_test
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWithLogger tests startup diagnostics logging
+func TestWithLogger(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	yamlFile := writeTempFile(t, tmpDir, "config.yaml", `database:
+  host: yaml-host
+  password: s3cr3t`)
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	var cfg dumpConfig
+	err := config.Load(&cfg, config.WithLocalYAML(yamlFile), config.WithLogger(logger))
+	require.NoError(t, err)
+
+	out := buf.String()
+	assert.Contains(t, out, `level=DEBUG msg="config source loaded" source=yaml path=`+yamlFile+` keys=2`)
+	assert.Contains(t, out, `source=dotenv`)
+	assert.Contains(t, out, `source=env`)
+	assert.Contains(t, out, `level=INFO msg="config loaded"`)
+	assert.Contains(t, out, "yaml-host")
+	assert.NotContains(t, out, "s3cr3t")
+}
@@ -0,0 +1,40 @@
+package config
+
+import "strings"
+
+// GenerateMarkdown returns a Markdown table documenting every key of the struct T:
+// its type, default value, environment variable and description from the `desc` tag.
+func GenerateMarkdown[T any](opts ...MarshalOption) ([]byte, error) {
+	v, err := defaults[T]()
+	if err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	b.WriteString("| Key | Type | Default | Environment | Description |\n")
+	b.WriteString("| --- | --- | --- | --- | --- |\n")
+	for _, f := range exampleFields(v, newMarshalOptions(opts).tags) {
+		cells := []string{
+			code(f.key),
+			code(indirect(f.field.Type).String()),
+			code(envValue(f.value)),
+			code(f.env),
+			strings.ReplaceAll(exampleComment(f.field), "\n", "<br>"),
+		}
+		for i, c := range cells {
+			cells[i] = strings.ReplaceAll(c, "|", `\|`)
+		}
+		b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+
+	return []byte(b.String()), nil
+}
+
+// code formats s as inline code, or returns it empty.
+func code(s string) string {
+	if s == "" {
+		return ""
+	}
+
+	return "`" + s + "`"
+}
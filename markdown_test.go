@@ -0,0 +1,29 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGenerateMarkdown tests generating a Markdown table of configuration keys
+func TestGenerateMarkdown(t *testing.T) {
+	b, err := config.GenerateMarkdown[exampleConfig]()
+	require.NoError(t, err)
+
+	assert.Equal(t, "| Key | Type | Default | Environment | Description |\n"+
+		"| --- | --- | --- | --- | --- |\n"+
+		"| `server.listen` | `string` | `:8080` | `SERVER__LISTEN` | Address to listen on |\n"+
+		"| `server.timeout` | `config.Duration` | `30s` | `SERVER__TIMEOUT` | Request timeout |\n"+
+		"| `format` | `config_test.logFormat` | `json` | `FORMAT` | Log format<br>one of: json, text |\n"+
+		"| `password` | `config.Secret` | `******` | `PASSWORD` |  |\n"+
+		"| `tags` | `[]string` |  | `TAGS` |  |\n", string(b))
+}
+
+// TestGenerateMarkdownUnsupportedTarget tests that non-struct types are rejected
+func TestGenerateMarkdownUnsupportedTarget(t *testing.T) {
+	_, err := config.GenerateMarkdown[[]string]()
+	require.ErrorIs(t, err, config.ErrUnsupportedTarget)
+}
@@ -0,0 +1,142 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const saveFileMode = 0o600
+
+type marshalOptions struct {
+	redact bool
+	tags   []string
+	delim  string
+}
+
+// MarshalOption configures Marshal, Save and the other functions rendering configuration structs,
+// such as Dump, Env, Diff and the generators.
+type MarshalOption func(*marshalOptions)
+
+func newMarshalOptions(opts []MarshalOption) marshalOptions {
+	o := marshalOptions{redact: false, tags: []string{tagName}, delim: defaultKeyDelimiter}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// Redact masks values of secret fields, as Dump does. Only Marshal and Save are affected.
+func Redact() MarshalOption {
+	return func(o *marshalOptions) {
+		o.redact = true
+	}
+}
+
+// Tags sets the struct tags naming keys, as WithTag does for Load, so structs loaded with WithTag
+// are rendered with the same keys.
+func Tags(tag string, fallbacks ...string) MarshalOption {
+	if tag == "" {
+		tag = tagName
+	}
+
+	return withTags(append([]string{tag}, fallbacks...))
+}
+
+// KeyDelimiter sets the delimiter of the key paths reported by Flatten and Diff, as WithKeyDelimiter
+// does for Load.
+func KeyDelimiter(delim string) MarshalOption {
+	return func(o *marshalOptions) {
+		if delim == "" {
+			delim = defaultKeyDelimiter
+		}
+		o.delim = delim
+	}
+}
+
+// MarshalOptionsFor returns the options rendering structs with the tags and key delimiter set
+// by the given Load options, e.g. for tools that get the options of a service.
+func MarshalOptionsFor(opts ...Option) []MarshalOption {
+	options := newOptions()
+	options.apply(opts...)
+
+	return []MarshalOption{withTags(options.tags), KeyDelimiter(options.delim)}
+}
+
+// withTags renders with the tags of a Load, see options.tags.
+func withTags(tags []string) MarshalOption {
+	return func(o *marshalOptions) {
+		o.tags = tags
+	}
+}
+
+// Marshal renders the effective configuration in the given format.
+//
+// Unlike Dump, secrets are written as-is unless Redact is given, so the output can be loaded back.
+func Marshal(c any, format Format, opts ...MarshalOption) ([]byte, error) {
+	o := newMarshalOptions(opts)
+	m, err := toOrderedMap(c, o.redact, o.tags)
+	if err != nil {
+		return nil, err
+	}
+
+	return encode(m, format)
+}
+
+// Save writes the effective configuration to the given path, in the format matching its
+// extension: `.yaml`, `.yml` or `.json`.
+//
+// The file is replaced atomically and, as it may contain secrets, is readable by the owner only.
+func Save(c any, path string, opts ...MarshalOption) error {
+	format, err := formatOf(path)
+	if err != nil {
+		return err
+	}
+
+	b, err := Marshal(c, format, opts...)
+	if err != nil {
+		return err
+	}
+
+	return writeFile(path, b)
+}
+
+// writeFile atomically replaces the file at path with data, readable by the owner only.
+func writeFile(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	defer func() { _ = os.Remove(f.Name()) }() // no-op once renamed
+
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	if err := f.Chmod(saveFileMode); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("chmod %s: %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("close %s: %w", path, err)
+	}
+
+	if err := os.Rename(f.Name(), path); err != nil {
+		return fmt.Errorf("rename %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func formatOf(path string) (Format, error) {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		return FormatYAML, nil
+	case ".json":
+		return FormatJSON, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnsupportedFormat, ext)
+	}
+}
@@ -0,0 +1,54 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMarshal tests rendering the effective configuration with and without redaction
+func TestMarshal(t *testing.T) {
+	cfg := newDumpConfig()
+
+	b, err := config.Marshal(cfg, config.FormatYAML)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), "s3cr3t")
+
+	b, err = config.Marshal(cfg, config.FormatYAML, config.Redact())
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), "s3cr3t")
+
+	_, err = config.Marshal(cfg, "toml")
+	require.ErrorIs(t, err, config.ErrUnsupportedFormat)
+}
+
+// TestSave tests that a saved configuration loads back into the same values
+func TestSave(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+
+	cfg := TestConfig{}
+	cfg.Database.Host = "db.local"
+	cfg.Database.Password = "s3cr3t"
+	cfg.Server.Port = 8080
+	cfg.FeatureFlags = map[string]bool{"beta": true}
+
+	for _, name := range []string{"config.yaml", "config.json"} {
+		path := filepath.Join(tmpDir, name)
+		require.NoError(t, config.Save(cfg, path))
+
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+		var loaded TestConfig
+		require.NoError(t, config.Load(&loaded, config.WithLocalYAML(path)))
+		assert.Equal(t, cfg, loaded)
+	}
+
+	require.ErrorIs(t, config.Save(cfg, filepath.Join(tmpDir, "config.toml")), config.ErrUnsupportedFormat)
+}
@@ -0,0 +1,154 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/knadh/koanf/v2"
+)
+
+var errReadBytes = errors.New("read bytes is not supported")
+
+// SliceMerge combines a list set by an earlier source (dst) with the list set for
+// the same key by a later source (src).
+//
+// SliceReplace, SliceAppend and MergeByKey are provided; custom functions may be used as well.
+type SliceMerge func(dst, src []any) []any
+
+// SliceReplace replaces the earlier list with the later one. This is the default.
+func SliceReplace(_, src []any) []any {
+	return src
+}
+
+// SliceAppend appends the items of the later list to the earlier one.
+func SliceAppend(dst, src []any) []any {
+	out := make([]any, 0, len(dst)+len(src))
+	out = append(out, dst...)
+	return append(out, src...)
+}
+
+// MergeByKey merges lists of objects by the value of the given field, e.g. MergeByKey("name").
+// Items of the later list with the same key are deep-merged into the earlier items,
+// others are appended. Items without the key are always appended.
+func MergeByKey(key string) SliceMerge {
+	return func(dst, src []any) []any {
+		out := make([]any, 0, len(dst)+len(src))
+		out = append(out, dst...)
+
+		for _, item := range src {
+			m, ok := item.(map[string]any)
+			if i := indexByKey(out, key, m); ok && i >= 0 {
+				em, _ := out[i].(map[string]any)
+				out[i] = mergeMaps(copyMap(em), m, SliceReplace)
+				continue
+			}
+			out = append(out, item)
+		}
+
+		return out
+	}
+}
+
+// indexByKey returns the index of the object in items with the same key value as m, or -1.
+func indexByKey(items []any, key string, m map[string]any) int {
+	id, ok := m[key]
+	if !ok {
+		return -1
+	}
+
+	for i, item := range items {
+		if im, ok := item.(map[string]any); ok && reflect.DeepEqual(im[key], id) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// MergeFunc merges the values of a later source (src) into the values merged from
+// earlier sources (dest) by mutating dest. Both are nested maps keyed by key path segments.
+type MergeFunc func(src, dest map[string]any) error
+
+// DeepMerge merges src into dest the way Load does by default: nested maps are merged
+// recursively and any other value, including lists, replaces the earlier one.
+// It is meant to be used as a fallback by custom merge functions.
+func DeepMerge(src, dest map[string]any) {
+	mergeMaps(dest, src, SliceReplace)
+}
+
+// mergeLayer merges the values of a source loaded into lk into k.
+func mergeLayer(k, lk *koanf.Koanf, options *options) error {
+	merge := options.merge
+	if merge == nil && options.sliceMerge != nil {
+		merge = func(src, dest map[string]any) error {
+			mergeMaps(dest, src, options.sliceMerge)
+			return nil
+		}
+	}
+
+	if merge == nil {
+		if err := k.Merge(lk); err != nil {
+			return fmt.Errorf("merge: %w", err)
+		}
+		return nil
+	}
+
+	err := k.Load(rawProvider(lk.Raw()), nil, koanf.WithMergeFunc(merge))
+	if err != nil {
+		return fmt.Errorf("merge: %w", err)
+	}
+
+	return nil
+}
+
+// mergeMaps deep-merges src into dst, combining lists with sliceMerge, and returns dst.
+func mergeMaps(dst, src map[string]any, sliceMerge SliceMerge) map[string]any {
+	for key, sv := range src {
+		dv, ok := dst[key]
+		if !ok {
+			dst[key] = sv
+			continue
+		}
+
+		switch s := sv.(type) {
+		case map[string]any:
+			if d, ok := dv.(map[string]any); ok {
+				dst[key] = mergeMaps(d, s, sliceMerge)
+				continue
+			}
+		case []any:
+			if d, ok := dv.([]any); ok {
+				dst[key] = sliceMerge(d, s)
+				continue
+			}
+		}
+
+		dst[key] = sv
+	}
+
+	return dst
+}
+
+func copyMap(m map[string]any) map[string]any {
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		if nested, ok := v.(map[string]any); ok {
+			v = copyMap(nested)
+		}
+		out[k] = v
+	}
+
+	return out
+}
+
+// rawProvider is a koanf.Provider returning an already parsed map.
+type rawProvider map[string]any
+
+func (p rawProvider) ReadBytes() ([]byte, error) {
+	return nil, errReadBytes
+}
+
+func (p rawProvider) Read() (map[string]any, error) {
+	return p, nil
+}
@@ -0,0 +1,96 @@
+package config_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type upstream struct {
+	Name   string `koanf:"name"`
+	URL    string `koanf:"url"`
+	Weight int    `koanf:"weight"`
+}
+
+type mergeConfig struct {
+	Upstreams []upstream `koanf:"upstreams"`
+	Tags      []string   `koanf:"tags"`
+}
+
+// loadMergeConfig loads a base YAML file overlaid by a .env file
+func loadMergeConfig(t *testing.T, opts ...config.Option) mergeConfig {
+	t.Helper()
+	tmpDir := t.TempDir()
+	withDotEnv(t, tmpDir, `UPSTREAMS=[{"name": "b", "weight": 5}, {"name": "c", "url": "http://c"}]
+TAGS=["extra"]`)
+	yamlFile := writeTempFile(t, tmpDir, "config.yaml", `upstreams:
+  - name: a
+    url: http://a
+  - name: b
+    url: http://b
+    weight: 1
+tags: [base]`)
+
+	var cfg mergeConfig
+	require.NoError(t, config.Load(&cfg, append(opts, config.WithLocalYAML(yamlFile))...))
+	return cfg
+}
+
+// TestSliceMergeReplace tests the default list merge strategy
+func TestSliceMergeReplace(t *testing.T) {
+	cfg := loadMergeConfig(t)
+
+	assert.Equal(t, []upstream{{Name: "b", Weight: 5}, {Name: "c", URL: "http://c"}}, cfg.Upstreams)
+	assert.Equal(t, []string{"extra"}, cfg.Tags)
+}
+
+// TestSliceMergeAppend tests extending lists from later sources
+func TestSliceMergeAppend(t *testing.T) {
+	cfg := loadMergeConfig(t, config.WithSliceMerge(config.SliceAppend))
+
+	assert.Len(t, cfg.Upstreams, 4)
+	assert.Equal(t, []string{"base", "extra"}, cfg.Tags)
+}
+
+// TestSliceMergeByKey tests merging lists of objects by a key field
+func TestSliceMergeByKey(t *testing.T) {
+	cfg := loadMergeConfig(t, config.WithSliceMerge(config.MergeByKey("name")))
+
+	assert.Equal(t, []upstream{
+		{Name: "a", URL: "http://a"},
+		{Name: "b", URL: "http://b", Weight: 5},
+		{Name: "c", URL: "http://c"},
+	}, cfg.Upstreams)
+	assert.Equal(t, []string{"base", "extra"}, cfg.Tags)
+}
+
+// TestWithMergeFunc tests custom merge functions
+func TestWithMergeFunc(t *testing.T) {
+	// first wins for tags, default merge for everything else
+	firstWins := func(src, dest map[string]any) error {
+		if _, ok := dest["tags"]; ok {
+			delete(src, "tags")
+		}
+		config.DeepMerge(src, dest)
+		return nil
+	}
+
+	cfg := loadMergeConfig(t, config.WithMergeFunc(firstWins))
+
+	assert.Equal(t, []string{"base"}, cfg.Tags)
+	assert.Equal(t, []upstream{{Name: "b", Weight: 5}, {Name: "c", URL: "http://c"}}, cfg.Upstreams)
+}
+
+// TestWithMergeFuncError tests merge function error propagation
+func TestWithMergeFuncError(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("SERVER__PORT", "8080")
+
+	errConflict := errors.New("conflict")
+	var cfg TestConfig
+	err := config.Load(&cfg, config.WithMergeFunc(func(map[string]any, map[string]any) error { return errConflict }))
+	require.ErrorIs(t, err, errConflict)
+}
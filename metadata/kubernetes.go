@@ -0,0 +1,128 @@
+package metadata
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultPodInfoDir is the conventional mount path of the Downward API volume.
+	DefaultPodInfoDir = "/etc/podinfo"
+
+	podPrefix = "pod"
+)
+
+// Kubernetes returns a source reading the pod identity exposed by the Downward API under the `pod` key:
+// name, namespace, uid, ip, node, labels and annotations.
+//
+// Values are read from the environment variables POD_NAME, POD_NAMESPACE, POD_UID, POD_IP and NODE_NAME,
+// and from the files name, namespace, uid, labels and annotations of the Downward API volume mounted at dir,
+// e.g. DefaultPodInfoDir, which take precedence. Missing variables and files are skipped.
+//
+// Dots in label and annotation keys are replaced by underscores, since dots separate configuration keys:
+// app.kubernetes.io/name is available as pod.labels.app_kubernetes_io/name.
+func Kubernetes(dir string, opts ...Option) *Source {
+	s := newSource("kubernetes", "", map[string]string{}, append([]Option{WithPrefix(podPrefix)}, opts...))
+	s.fetch = func(context.Context, *Source) (map[string]any, error) {
+		return readPodInfo(dir)
+	}
+
+	return s
+}
+
+func readPodInfo(dir string) (map[string]any, error) {
+	m := map[string]any{}
+	for key, name := range map[string]string{
+		"name":      "POD_NAME",
+		"namespace": "POD_NAMESPACE",
+		"uid":       "POD_UID",
+		"ip":        "POD_IP",
+		"node":      "NODE_NAME",
+	} {
+		if v, ok := os.LookupEnv(name); ok {
+			m[key] = v
+		}
+	}
+
+	for _, key := range []string{"name", "namespace", "uid"} {
+		b, err := readPodFile(dir, key)
+		if err != nil {
+			return nil, err
+		}
+		if b != nil {
+			m[key] = strings.TrimSpace(string(b))
+		}
+	}
+
+	for _, key := range []string{"labels", "annotations"} {
+		b, err := readPodFile(dir, key)
+		if err != nil {
+			return nil, err
+		}
+		if b == nil {
+			continue
+		}
+
+		values, err := parsePodMap(b)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", key, err)
+		}
+		m[key] = values
+	}
+
+	return m, nil
+}
+
+// readPodFile returns the contents of the named file in dir, or nil if it does not exist.
+func readPodFile(dir, name string) ([]byte, error) {
+	if dir == "" {
+		return nil, nil
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read pod info: %w", err)
+	}
+
+	return b, nil
+}
+
+// parsePodMap parses the `key="value"` lines of the labels and annotations files.
+func parsePodMap(b []byte) (map[string]any, error) {
+	m := map[string]any{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	scanner.Buffer(nil, maxResponseSize)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+
+		key, quoted, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid line %q", line)
+		}
+		value, err := strconv.Unquote(quoted)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of %s: %w", key, err)
+		}
+
+		m[strings.ReplaceAll(key, ".", "_")] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read: %w", err)
+	}
+
+	return m, nil
+}
@@ -0,0 +1,56 @@
+package metadata_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-core-fx/config"
+	"github.com/go-core-fx/config/metadata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type podConfig struct {
+	Pod struct {
+		Name        string            `koanf:"name"`
+		Namespace   string            `koanf:"namespace"`
+		Node        string            `koanf:"node"`
+		Labels      map[string]string `koanf:"labels"`
+		Annotations map[string]string `koanf:"annotations"`
+	} `koanf:"pod"`
+}
+
+// TestKubernetes tests reading the pod identity from Downward API files and environment variables
+func TestKubernetes(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("POD_NAME", "env-name")
+	t.Setenv("NODE_NAME", "node-1")
+
+	dir := t.TempDir()
+	for name, data := range map[string]string{
+		"name":        "api-7d9f",
+		"labels":      "app=\"api\"\napp.kubernetes.io/version=\"1.2.3\"\n",
+		"annotations": "note=\"multi\\nline\"\n",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644))
+	}
+
+	var c podConfig
+	require.NoError(t, config.Load(&c, config.WithSource(metadata.Kubernetes(dir))))
+	assert.Equal(t, "api-7d9f", c.Pod.Name)
+	assert.Empty(t, c.Pod.Namespace)
+	assert.Equal(t, "node-1", c.Pod.Node)
+	assert.Equal(t, map[string]string{"app": "api", "app_kubernetes_io/version": "1.2.3"}, c.Pod.Labels)
+	assert.Equal(t, map[string]string{"note": "multi\nline"}, c.Pod.Annotations)
+}
+
+// TestKubernetesInvalid tests that malformed label files fail the load
+func TestKubernetesInvalid(t *testing.T) {
+	t.Chdir(t.TempDir())
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "labels"), []byte("app=api\n"), 0o644))
+
+	var c podConfig
+	require.Error(t, config.Load(&c, config.WithSource(metadata.Kubernetes(dir))))
+}
@@ -0,0 +1,347 @@
+// Package metadata provides configuration sources reading cloud instance metadata, such as the
+// region and instance ID, and user-data, or the pod identity from the Kubernetes Downward API
+// into a configuration subtree.
+//
+//	config.Load(&cfg,
+//		config.WithSource(metadata.EC2()),
+//		config.WithSourceTimeout(2*time.Second),
+//	)
+//
+// With the default prefix, the instance ID is available at instance.id and user-data,
+// parsed as YAML or JSON, at instance.user_data. Gzipped user-data is decompressed, and user-data
+// that is not a map, such as a shell script, is skipped.
+package metadata
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/go-core-fx/config"
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	// DefaultPrefix is the key of the subtree holding the metadata.
+	DefaultPrefix = "instance"
+
+	userDataKey     = "user_data"
+	maxResponseSize = 1 << 20
+)
+
+var (
+	// ErrUnavailable is returned if the metadata service cannot be reached or returns an error,
+	// e.g. when not running on the provider.
+	ErrUnavailable = errors.New("metadata unavailable")
+
+	errNotFound = errors.New("not found")
+	errTooLarge = errors.New("too large")
+)
+
+// gzipMagic starts gzip-compressed data, as cloud-init accepts for user-data.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// Source is a config.Source reading cloud instance metadata.
+type Source struct {
+	name     string
+	prefix   string
+	endpoint string
+	client   *http.Client
+	fields   map[string]string
+	userData bool
+	fetch    func(ctx context.Context, s *Source) (map[string]any, error)
+	token    func(ctx context.Context, s *Source) (http.Header, error)
+}
+
+var _ config.Source = (*Source)(nil)
+
+// Option configures a Source.
+type Option func(*Source)
+
+// WithPrefix sets the key of the subtree holding the metadata, DefaultPrefix by default.
+// An empty prefix puts the metadata at the root.
+func WithPrefix(prefix string) Option {
+	return func(s *Source) {
+		s.prefix = prefix
+	}
+}
+
+// WithEndpoint sets the base URL of the metadata service, e.g. for tests.
+func WithEndpoint(endpoint string) Option {
+	return func(s *Source) {
+		s.endpoint = strings.TrimSuffix(endpoint, "/")
+	}
+}
+
+// WithHTTPClient sets the HTTP client, http.DefaultClient by default.
+func WithHTTPClient(client *http.Client) Option {
+	return func(s *Source) {
+		s.client = client
+	}
+}
+
+// WithField adds the metadata at the given path, relative to the instance metadata
+// of the provider, as key, e.g. WithField("ami", "ami-id") for EC2. It has no effect for ECS.
+func WithField(key, path string) Option {
+	return func(s *Source) {
+		s.fields[key] = path
+	}
+}
+
+// WithoutUserData skips reading user-data. Missing user-data is not an error.
+func WithoutUserData() Option {
+	return func(s *Source) {
+		s.userData = false
+	}
+}
+
+func newSource(name, endpoint string, fields map[string]string, opts []Option) *Source {
+	s := &Source{
+		name:     name,
+		prefix:   DefaultPrefix,
+		endpoint: endpoint,
+		client:   http.DefaultClient,
+		fields:   fields,
+		userData: true,
+		fetch:    nil,
+		token:    nil,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// EC2 returns a source reading EC2 instance metadata with IMDSv2: id, type, region, zone
+// and private_ip, and user-data.
+func EC2(opts ...Option) *Source {
+	s := newSource("ec2", "http://169.254.169.254", map[string]string{
+		"id":         "instance-id",
+		"type":       "instance-type",
+		"region":     "placement/region",
+		"zone":       "placement/availability-zone",
+		"private_ip": "local-ipv4",
+	}, opts)
+	s.token = ec2Token
+	s.fetch = func(ctx context.Context, s *Source) (map[string]any, error) {
+		return s.fetchFields(ctx, "/latest/meta-data/", "/latest/user-data")
+	}
+
+	return s
+}
+
+// GCE returns a source reading Google Compute Engine instance metadata: id, name, zone, region,
+// machine_type and project, and the user-data attribute.
+func GCE(opts ...Option) *Source {
+	s := newSource("gce", "http://metadata.google.internal", map[string]string{
+		"id":           "instance/id",
+		"name":         "instance/name",
+		"zone":         "instance/zone",
+		"machine_type": "instance/machine-type",
+		"project":      "project/project-id",
+	}, opts)
+	s.token = func(context.Context, *Source) (http.Header, error) {
+		return http.Header{"Metadata-Flavor": []string{"Google"}}, nil
+	}
+	s.fetch = func(ctx context.Context, s *Source) (map[string]any, error) {
+		m, err := s.fetchFields(ctx, "/computeMetadata/v1/", "/computeMetadata/v1/instance/attributes/user-data")
+		if err != nil {
+			return nil, err
+		}
+
+		// zone and machine type are returned as projects/<number>/zones/<zone>
+		for _, key := range []string{"zone", "machine_type"} {
+			if v, ok := m[key].(string); ok {
+				m[key] = v[strings.LastIndex(v, "/")+1:]
+			}
+		}
+		if zone, ok := m["zone"].(string); ok {
+			if i := strings.LastIndex(zone, "-"); i > 0 {
+				m["region"] = zone[:i]
+			}
+		}
+
+		return m, nil
+	}
+
+	return s
+}
+
+// ECS returns a source reading the ECS task metadata from $ECS_CONTAINER_METADATA_URI_V4:
+// cluster, task_arn, family, revision and zone. User-data is not available for tasks.
+func ECS(opts ...Option) *Source {
+	s := newSource("ecs", os.Getenv("ECS_CONTAINER_METADATA_URI_V4"), map[string]string{}, opts)
+	s.fetch = func(ctx context.Context, s *Source) (map[string]any, error) {
+		if s.endpoint == "" {
+			return nil, fmt.Errorf("%w: ECS_CONTAINER_METADATA_URI_V4 not set", ErrUnavailable)
+		}
+
+		b, err := s.get(ctx, "/task", http.Header{})
+		if err != nil {
+			return nil, fmt.Errorf("get task metadata: %w", err)
+		}
+
+		var task struct {
+			Cluster          string `json:"Cluster"`
+			TaskARN          string `json:"TaskARN"`
+			Family           string `json:"Family"`
+			Revision         string `json:"Revision"`
+			AvailabilityZone string `json:"AvailabilityZone"`
+		}
+		if err := json.Unmarshal(b, &task); err != nil {
+			return nil, fmt.Errorf("parse task metadata: %w", err)
+		}
+
+		return map[string]any{
+			"cluster":  task.Cluster,
+			"task_arn": task.TaskARN,
+			"family":   task.Family,
+			"revision": task.Revision,
+			"zone":     task.AvailabilityZone,
+		}, nil
+	}
+
+	return s
+}
+
+// Name returns the name of the provider, e.g. "ec2".
+func (s *Source) Name() string {
+	return s.name
+}
+
+// Load reads the metadata.
+func (s *Source) Load(ctx context.Context) (map[string]any, error) {
+	m, err := s.fetch(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.prefix == "" {
+		return m, nil
+	}
+
+	return map[string]any{s.prefix: m}, nil
+}
+
+// fetchFields reads the fields relative to base and the user-data at userData.
+func (s *Source) fetchFields(ctx context.Context, base, userData string) (map[string]any, error) {
+	header, err := s.token(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+
+	m := map[string]any{}
+	for key, path := range s.fields {
+		b, err := s.get(ctx, base+path, header)
+		if errors.Is(err, errNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		m[key] = strings.TrimSpace(string(b))
+	}
+
+	if !s.userData {
+		return m, nil
+	}
+
+	b, err := s.get(ctx, userData, header)
+	if errors.Is(err, errNotFound) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := parseUserData(b)
+	if err != nil {
+		return nil, err
+	}
+	if data != nil {
+		m[userDataKey] = data
+	}
+
+	return m, nil
+}
+
+// parseUserData parses user-data as a YAML or JSON map, decompressing it first if it is gzipped.
+// User-data that is not a map, such as a shell script, is not configuration and returns nil.
+func parseUserData(b []byte) (map[string]any, error) {
+	if bytes.HasPrefix(b, gzipMagic) {
+		r, err := gzip.NewReader(bytes.NewReader(b))
+		if err != nil {
+			return nil, fmt.Errorf("decompress user-data: %w", err)
+		}
+		defer r.Close()
+
+		if b, err = io.ReadAll(io.LimitReader(r, maxResponseSize+1)); err != nil {
+			return nil, fmt.Errorf("decompress user-data: %w", err)
+		}
+		if len(b) > maxResponseSize {
+			return nil, fmt.Errorf("decompress user-data: %w", errTooLarge)
+		}
+	}
+
+	var data any
+	if err := yaml.Unmarshal(b, &data); err != nil {
+		return nil, nil //nolint:nilerr // not YAML, e.g. a shell script
+	}
+	m, _ := data.(map[string]any)
+
+	return m, nil
+}
+
+func (s *Source) get(ctx context.Context, path string, header http.Header) ([]byte, error) {
+	return s.do(ctx, http.MethodGet, path, header)
+}
+
+func (s *Source) do(ctx context.Context, method, path string, header http.Header) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, s.endpoint+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrUnavailable, path, err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, errNotFound
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("%w: %s: %s", ErrUnavailable, path, resp.Status)
+	}
+
+	return b, nil
+}
+
+// ec2Token requests an IMDSv2 session token.
+func ec2Token(ctx context.Context, s *Source) (http.Header, error) {
+	b, err := s.do(ctx, http.MethodPut, "/latest/api/token", http.Header{
+		"X-Aws-Ec2-Metadata-Token-Ttl-Seconds": []string{"60"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get token: %w", err)
+	}
+
+	return http.Header{"X-Aws-Ec2-Metadata-Token": []string{string(b)}}, nil
+}
@@ -0,0 +1,156 @@
+package metadata_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-core-fx/config"
+	"github.com/go-core-fx/config/metadata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testConfig struct {
+	Instance struct {
+		ID       string `koanf:"id"`
+		Region   string `koanf:"region"`
+		Zone     string `koanf:"zone"`
+		Cluster  string `koanf:"cluster"`
+		UserData struct {
+			LogLevel string `koanf:"log_level"`
+		} `koanf:"user_data"`
+	} `koanf:"instance"`
+}
+
+func load(t *testing.T, src config.Source) testConfig {
+	t.Helper()
+	t.Chdir(t.TempDir())
+
+	var c testConfig
+	require.NoError(t, config.Load(&c, config.WithSource(src), config.WithEnviron(nil)))
+	return c
+}
+
+// TestEC2 tests reading EC2 metadata and user-data with an IMDSv2 token
+func TestEC2(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/latest/api/token" {
+			assert.Equal(t, http.MethodPut, r.Method)
+			_, _ = w.Write([]byte("token"))
+			return
+		}
+		if r.Header.Get("X-Aws-Ec2-Metadata-Token") != "token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/latest/meta-data/instance-id":
+			_, _ = w.Write([]byte("i-123"))
+		case "/latest/meta-data/placement/region":
+			_, _ = w.Write([]byte("eu-west-1"))
+		case "/latest/user-data":
+			_, _ = w.Write([]byte("log_level: debug\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	c := load(t, metadata.EC2(metadata.WithEndpoint(srv.URL)))
+	assert.Equal(t, "i-123", c.Instance.ID)
+	assert.Equal(t, "eu-west-1", c.Instance.Region)
+	assert.Empty(t, c.Instance.Zone)
+	assert.Equal(t, "debug", c.Instance.UserData.LogLevel)
+}
+
+// TestGCE tests reading GCE metadata and deriving the region from the zone
+func TestGCE(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata-Flavor") != "Google" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/computeMetadata/v1/instance/id":
+			_, _ = w.Write([]byte("123"))
+		case "/computeMetadata/v1/instance/zone":
+			_, _ = w.Write([]byte("projects/42/zones/europe-west1-b"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	c := load(t, metadata.GCE(metadata.WithEndpoint(srv.URL)))
+	assert.Equal(t, "123", c.Instance.ID)
+	assert.Equal(t, "europe-west1-b", c.Instance.Zone)
+	assert.Equal(t, "europe-west1", c.Instance.Region)
+}
+
+// TestECS tests reading the ECS task metadata
+func TestECS(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v4/task", r.URL.Path)
+		_, _ = w.Write([]byte(`{"Cluster": "prod", "AvailabilityZone": "eu-west-1a"}`))
+	}))
+	defer srv.Close()
+
+	c := load(t, metadata.ECS(metadata.WithEndpoint(srv.URL+"/v4")))
+	assert.Equal(t, "prod", c.Instance.Cluster)
+	assert.Equal(t, "eu-west-1a", c.Instance.Zone)
+}
+
+// TestUnavailable tests that an unreachable metadata service fails the load
+func TestUnavailable(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+	t.Chdir(t.TempDir())
+
+	var c testConfig
+	err := config.Load(&c, config.WithSource(metadata.EC2(metadata.WithEndpoint(srv.URL))), config.WithEnviron(nil))
+	require.ErrorIs(t, err, metadata.ErrUnavailable)
+}
+
+// TestEC2UserData tests that gzipped user-data is decompressed and scripts are skipped
+func TestEC2UserData(t *testing.T) {
+	var gz bytes.Buffer
+	w := gzip.NewWriter(&gz)
+	_, _ = w.Write([]byte("log_level: warn\n"))
+	require.NoError(t, w.Close())
+
+	for name, tc := range map[string]struct {
+		userData []byte
+		logLevel string
+	}{
+		"gzip":   {userData: gz.Bytes(), logLevel: "warn"},
+		"script": {userData: []byte("#!/bin/bash\nyum install -y nginx\n"), logLevel: ""},
+		"scalar": {userData: []byte("hello"), logLevel: ""},
+	} {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/latest/api/token":
+					_, _ = w.Write([]byte("token"))
+				case "/latest/meta-data/instance-id":
+					_, _ = w.Write([]byte("i-123"))
+				case "/latest/user-data":
+					_, _ = w.Write(tc.userData)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer srv.Close()
+
+			c := load(t, metadata.EC2(metadata.WithEndpoint(srv.URL)))
+			assert.Equal(t, "i-123", c.Instance.ID)
+			assert.Equal(t, tc.logLevel, c.Instance.UserData.LogLevel)
+		})
+	}
+}
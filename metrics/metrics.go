@@ -0,0 +1,151 @@
+// Package metrics exposes Prometheus metrics about configuration loads.
+package metrics
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/go-core-fx/config"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	resultSuccess = "success"
+	resultFailure = "failure"
+
+	halfOpen = 0.5
+)
+
+// Collector is a prometheus.Collector tracking configuration loads.
+//
+// The first Load observed by the collector is the initial load, every subsequent one is treated as a reload.
+type Collector struct {
+	mu     sync.Mutex
+	loaded bool
+
+	loads          *prometheus.CounterVec
+	reloadFailures prometheus.Counter
+	lastReload     prometheus.Gauge
+	hash           prometheus.Gauge
+}
+
+// NewCollector creates a new Collector. Register it with a prometheus.Registerer and pass Option to Load.
+func NewCollector() *Collector {
+	return &Collector{
+		mu:     sync.Mutex{},
+		loaded: false,
+
+		loads: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "config_load_total",
+			Help: "Total number of configuration loads by result.",
+		}, []string{"result"}),
+		reloadFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "config_reload_failures_total",
+			Help: "Total number of failed configuration reloads.",
+		}),
+		lastReload: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "config_last_reload_timestamp_seconds",
+			Help: "Timestamp of the last successful configuration load.",
+		}),
+		hash: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "config_hash",
+			Help: "Hash of the currently loaded configuration.",
+		}),
+	}
+}
+
+// Option returns a config option that reports every Load to the collector.
+func (c *Collector) Option() config.Option {
+	return config.OnLoad(c.observe)
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.loads.Describe(ch)
+	c.reloadFailures.Describe(ch)
+	c.lastReload.Describe(ch)
+	c.hash.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.loads.Collect(ch)
+	c.reloadFailures.Collect(ch)
+	c.lastReload.Collect(ch)
+	c.hash.Collect(ch)
+}
+
+func (c *Collector) observe(info config.LoadInfo) {
+	c.mu.Lock()
+	reload := c.loaded
+	c.loaded = true
+	c.mu.Unlock()
+
+	if info.Err != nil {
+		c.loads.WithLabelValues(resultFailure).Inc()
+		if reload {
+			c.reloadFailures.Inc()
+		}
+		return
+	}
+
+	c.loads.WithLabelValues(resultSuccess).Inc()
+	c.lastReload.Set(float64(time.Now().Unix()))
+	c.hash.Set(hashValue(info.Hash))
+}
+
+// hashValue converts the leading bytes of a hex digest into a gauge value.
+func hashValue(digest string) float64 {
+	b, err := hex.DecodeString(digest)
+	if err != nil || len(b) < 8 {
+		return 0
+	}
+
+	// Gauges are float64, so only the first 48 bits are used to keep the value exact.
+	return float64(binary.BigEndian.Uint64(b) >> 16)
+}
+
+// BreakerCollector is a prometheus.Collector exposing the circuit states of a config.CircuitBreaker.
+type BreakerCollector struct {
+	breaker *config.CircuitBreaker
+	desc    *prometheus.Desc
+}
+
+// NewBreakerCollector creates a BreakerCollector for the given breaker.
+func NewBreakerCollector(b *config.CircuitBreaker) *BreakerCollector {
+	return &BreakerCollector{
+		breaker: b,
+		desc: prometheus.NewDesc(
+			"config_source_circuit_open",
+			"Whether the circuit of a configuration source is open (1) or half-open (0.5).",
+			[]string{"source"}, nil,
+		),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *BreakerCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect implements prometheus.Collector.
+func (c *BreakerCollector) Collect(ch chan<- prometheus.Metric) {
+	for source, state := range c.breaker.States() {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, breakerValue(state), source)
+	}
+}
+
+func breakerValue(state config.BreakerState) float64 {
+	switch state {
+	case config.BreakerOpen:
+		return 1
+	case config.BreakerHalfOpen:
+		return halfOpen
+	case config.BreakerClosed:
+		return 0
+	default:
+		return 0
+	}
+}
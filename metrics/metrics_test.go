@@ -0,0 +1,91 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-core-fx/config"
+	"github.com/go-core-fx/config/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testConfig struct {
+	Host string `koanf:"host"`
+}
+
+// TestCollector tests load and reload metrics
+func TestCollector(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+
+	c := metrics.NewCollector()
+	reg := prometheus.NewPedanticRegistry()
+	require.NoError(t, reg.Register(c))
+
+	var cfg testConfig
+	require.NoError(t, config.Load(&cfg, c.Option()))
+
+	invalid := filepath.Join(tmpDir, "invalid.yaml")
+	require.NoError(t, os.WriteFile(invalid, []byte(`invalid: yaml: content: [`), 0o644))
+	require.Error(t, config.Load(&cfg, config.WithLocalYAML(invalid), c.Option()))
+
+	expected := `
+# HELP config_load_total Total number of configuration loads by result.
+# TYPE config_load_total counter
+config_load_total{result="failure"} 1
+config_load_total{result="success"} 1
+# HELP config_reload_failures_total Total number of failed configuration reloads.
+# TYPE config_reload_failures_total counter
+config_reload_failures_total 1
+`
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected),
+		"config_load_total", "config_reload_failures_total"))
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+
+	gauges := map[string]float64{}
+	for _, f := range families {
+		if f.GetType() == dto.MetricType_GAUGE {
+			gauges[f.GetName()] = f.GetMetric()[0].GetGauge().GetValue()
+		}
+	}
+	assert.Positive(t, gauges["config_last_reload_timestamp_seconds"])
+	assert.Positive(t, gauges["config_hash"])
+}
+
+// TestBreakerCollector tests exposing circuit states
+func TestBreakerCollector(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	b := config.NewCircuitBreaker(1, config.ConstantBackoff(time.Hour, 1))
+	reg := prometheus.NewPedanticRegistry()
+	require.NoError(t, reg.Register(metrics.NewBreakerCollector(b)))
+
+	var cfg testConfig
+	require.Error(t, config.Load(&cfg, config.WithSource(failingSource{}), config.WithCircuitBreaker(b)))
+
+	expected := `
+# HELP config_source_circuit_open Whether the circuit of a configuration source is open (1) or half-open (0.5).
+# TYPE config_source_circuit_open gauge
+config_source_circuit_open{source="remote"} 1
+`
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "config_source_circuit_open"))
+}
+
+var errUnavailable = errors.New("unavailable")
+
+type failingSource struct{}
+
+func (failingSource) Name() string { return "remote" }
+
+func (failingSource) Load(context.Context) (map[string]any, error) { return nil, errUnavailable }
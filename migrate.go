@@ -0,0 +1,53 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/knadh/koanf/v2"
+)
+
+const versionKey = "config_version"
+
+var ErrMigration = errors.New("migration failed")
+
+// Migration transforms the raw configuration of a source from one version to the next, e.g. by renaming
+// or restructuring keys, in place.
+type Migration func(m map[string]any) error
+
+// migrate runs the migrations registered for the `config_version` set by a source, in order,
+// and returns the migrated configuration with the version updated. Sources without a version are
+// assumed to be current.
+func migrate(lk *koanf.Koanf, migrations map[int]Migration, delim string) (*koanf.Koanf, error) {
+	if len(migrations) == 0 || !lk.Exists(versionKey) {
+		return lk, nil
+	}
+
+	version, err := strconv.Atoi(lk.String(versionKey))
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid %s %q", ErrMigration, versionKey, lk.String(versionKey))
+	}
+
+	fn, ok := migrations[version]
+	if !ok {
+		return lk, nil
+	}
+
+	m := lk.Raw()
+	for ok {
+		if err := fn(m); err != nil {
+			return nil, fmt.Errorf("%w: version %d: %w", ErrMigration, version, err)
+		}
+		version++
+		fn, ok = migrations[version]
+	}
+	m[versionKey] = version
+
+	migrated := koanf.New(delim)
+	if err := migrated.Load(rawProvider(m), nil); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMigration, err)
+	}
+
+	return migrated, nil
+}
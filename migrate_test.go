@@ -0,0 +1,66 @@
+package config_test
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type versionedConfig struct {
+	ConfigVersion int `koanf:"config_version"`
+	Database      struct {
+		Host string `koanf:"host"`
+		Port int    `koanf:"port"`
+	} `koanf:"database"`
+}
+
+// TestMigrations tests chaining migrations of an old config file
+func TestMigrations(t *testing.T) {
+	files := fstest.MapFS{"config.yaml": {Data: []byte("config_version: 1\ndb_host: old\ndb_port: 5432\n")}}
+	migrations := []config.Option{
+		config.WithMigration(1, func(m map[string]any) error {
+			m["database"] = map[string]any{"hostname": m["db_host"], "port": m["db_port"]}
+			delete(m, "db_host")
+			delete(m, "db_port")
+			return nil
+		}),
+		config.WithMigration(2, func(m map[string]any) error {
+			db, _ := m["database"].(map[string]any)
+			db["host"] = db["hostname"]
+			delete(db, "hostname")
+			return nil
+		}),
+	}
+
+	var c versionedConfig
+	require.NoError(t, loadYAML(t, &c, files, migrations...))
+	assert.Equal(t, 3, c.ConfigVersion)
+	assert.Equal(t, "old", c.Database.Host)
+	assert.Equal(t, 5432, c.Database.Port)
+
+	// current files and sources without a version are left alone
+	c = versionedConfig{}
+	files["config.yaml"] = &fstest.MapFile{Data: []byte("config_version: 3\ndatabase:\n  host: new\n")}
+	require.NoError(t, loadYAML(t, &c, files, append(migrations,
+		config.WithEnviron(map[string]string{"DATABASE__PORT": "6432"}))...))
+	assert.Equal(t, "new", c.Database.Host)
+	assert.Equal(t, 6432, c.Database.Port)
+}
+
+// TestMigrationError tests failing migrations and invalid versions
+func TestMigrationError(t *testing.T) {
+	errBroken := errors.New("broken")
+	fail := config.WithMigration(1, func(map[string]any) error { return errBroken })
+
+	var c versionedConfig
+	err := loadYAML(t, &c, fstest.MapFS{"config.yaml": {Data: []byte("config_version: 1\n")}}, fail)
+	require.ErrorIs(t, err, config.ErrMigration)
+	require.ErrorIs(t, err, errBroken)
+
+	err = loadYAML(t, &c, fstest.MapFS{"config.yaml": {Data: []byte("config_version: one\n")}}, fail)
+	require.ErrorIs(t, err, config.ErrMigration)
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+var ErrPrefixCollision = errors.New("config prefix collision")
+
+// Registry collects the configuration structs of the modules of an application, so one load
+// populates all of them.
+type Registry struct {
+	mu      sync.Mutex
+	targets []LoadTarget
+}
+
+//nolint:gochecknoglobals // modules register in init functions
+var defaultRegistry = NewRegistry()
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{mu: sync.Mutex{}, targets: nil}
+}
+
+// Register adds the struct pointed to by c, populated from the keys under prefix, e.g. "database".
+// It is safe to call from init functions; collisions are reported by Load.
+func (r *Registry) Register(prefix string, c any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.targets = append(r.targets, Target(prefix, c))
+}
+
+// Load loads the configuration once into all registered structs, see LoadAll.
+//
+// It returns ErrPrefixCollision if two prefixes are equal or one is nested in the other,
+// e.g. "database" and "database.pool".
+func (r *Registry) Load(ctx context.Context, opts ...Option) error {
+	r.mu.Lock()
+	targets := r.targets
+	r.mu.Unlock()
+
+	options := newOptions()
+	options.apply(opts...)
+	if err := checkPrefixes(targets, options.delim); err != nil {
+		return err
+	}
+
+	return LoadAllContext(ctx, opts, targets...)
+}
+
+// Register adds a struct to the default registry, see Registry.Register.
+func Register(prefix string, c any) {
+	defaultRegistry.Register(prefix, c)
+}
+
+// LoadRegistry loads the configuration into all structs of the default registry, see Registry.Load.
+func LoadRegistry(ctx context.Context, opts ...Option) error {
+	return defaultRegistry.Load(ctx, opts...)
+}
+
+func checkPrefixes(targets []LoadTarget, delim string) error {
+	for i, a := range targets {
+		for _, b := range targets[:i] {
+			if overlaps(a.prefix, b.prefix, delim) {
+				return fmt.Errorf("%w: %q and %q", ErrPrefixCollision, b.prefix, a.prefix)
+			}
+		}
+	}
+
+	return nil
+}
+
+func overlaps(a, b, delim string) bool {
+	if a == b {
+		return true
+	}
+	if a == "" || b == "" {
+		return false
+	}
+
+	return strings.HasPrefix(a, b+delim) || strings.HasPrefix(b, a+delim)
+}
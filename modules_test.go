@@ -0,0 +1,32 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type serverConfig struct {
+	Port int `koanf:"port"`
+}
+
+// TestRegistry tests loading the structs registered by several modules
+func TestRegistry(t *testing.T) {
+	r := config.NewRegistry()
+	var db databaseConfig
+	var server serverConfig
+	r.Register("database", &db)
+	r.Register("server", &server)
+
+	require.NoError(t, r.Load(t.Context(), config.WithEnviron(map[string]string{
+		"DATABASE__HOST": "db",
+		"SERVER__PORT":   "80",
+	})))
+	assert.Equal(t, "db", db.Host)
+	assert.Equal(t, 80, server.Port)
+
+	r.Register("database.pool", &serverConfig{})
+	require.ErrorIs(t, r.Load(t.Context(), config.WithEnviron(nil)), config.ErrPrefixCollision)
+}
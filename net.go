@@ -0,0 +1,168 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"strconv"
+)
+
+var (
+	ErrInvalidIP       = errors.New("invalid ip address")
+	ErrInvalidCIDR     = errors.New("invalid cidr")
+	ErrInvalidHostPort = errors.New("invalid host:port")
+)
+
+// IP is an IPv4 or IPv6 address validated when the configuration is loaded.
+type IP struct {
+	addr netip.Addr
+}
+
+// Addr returns the parsed address.
+func (ip IP) Addr() netip.Addr {
+	return ip.addr
+}
+
+// IsZero reports whether the value is not set.
+func (ip IP) IsZero() bool {
+	return !ip.addr.IsValid()
+}
+
+// String returns the address as a string.
+func (ip IP) String() string {
+	if ip.IsZero() {
+		return ""
+	}
+
+	return ip.addr.String()
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (ip IP) MarshalText() ([]byte, error) {
+	return []byte(ip.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (ip *IP) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*ip = IP{}
+		return nil
+	}
+
+	addr, err := netip.ParseAddr(string(text))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidIP, err)
+	}
+
+	ip.addr = addr
+	return nil
+}
+
+// CIDR is an IP network prefix such as 10.0.0.0/8, validated when the configuration is loaded.
+type CIDR struct {
+	prefix netip.Prefix
+}
+
+// Prefix returns the parsed prefix.
+func (c CIDR) Prefix() netip.Prefix {
+	return c.prefix
+}
+
+// Contains reports whether the network includes the given address.
+func (c CIDR) Contains(addr netip.Addr) bool {
+	return c.prefix.Contains(addr)
+}
+
+// IsZero reports whether the value is not set.
+func (c CIDR) IsZero() bool {
+	return !c.prefix.IsValid()
+}
+
+// String returns the prefix in CIDR notation.
+func (c CIDR) String() string {
+	if c.IsZero() {
+		return ""
+	}
+
+	return c.prefix.String()
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (c CIDR) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (c *CIDR) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*c = CIDR{}
+		return nil
+	}
+
+	prefix, err := netip.ParsePrefix(string(text))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidCIDR, err)
+	}
+
+	c.prefix = prefix.Masked()
+	return nil
+}
+
+// HostPort is a host and port pair such as "localhost:8080" or ":8080",
+// validated when the configuration is loaded.
+type HostPort struct {
+	host string
+	port uint16
+}
+
+// Host returns the host part, which may be empty for listen addresses.
+func (hp HostPort) Host() string {
+	return hp.host
+}
+
+// Port returns the port number.
+func (hp HostPort) Port() uint16 {
+	return hp.port
+}
+
+// IsZero reports whether the value is not set.
+func (hp HostPort) IsZero() bool {
+	return hp == HostPort{}
+}
+
+// String returns the address in host:port form.
+func (hp HostPort) String() string {
+	if hp.IsZero() {
+		return ""
+	}
+
+	return net.JoinHostPort(hp.host, strconv.Itoa(int(hp.port)))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (hp HostPort) MarshalText() ([]byte, error) {
+	return []byte(hp.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (hp *HostPort) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*hp = HostPort{}
+		return nil
+	}
+
+	host, port, err := net.SplitHostPort(string(text))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidHostPort, err)
+	}
+
+	p, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return fmt.Errorf("%w: %q: invalid port", ErrInvalidHostPort, text)
+	}
+
+	hp.host = host
+	hp.port = uint16(p)
+	return nil
+}
@@ -0,0 +1,64 @@
+package config_test
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type netConfig struct {
+	BindIP    config.IP       `koanf:"bind_ip"`
+	Allowlist []config.CIDR   `koanf:"allowlist"`
+	Listen    config.HostPort `koanf:"listen"`
+}
+
+// TestNetTypes tests loading of network-aware types
+func TestNetTypes(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	yamlFile := writeTempFile(t, tmpDir, "config.yaml", `bind_ip: "::1"
+allowlist:
+  - 10.1.2.3/8
+  - 192.168.0.0/16
+listen: ":8080"`)
+
+	var cfg netConfig
+	require.NoError(t, config.Load(&cfg, config.WithLocalYAML(yamlFile)))
+
+	assert.Equal(t, netip.MustParseAddr("::1"), cfg.BindIP.Addr())
+	require.Len(t, cfg.Allowlist, 2)
+	assert.Equal(t, "10.0.0.0/8", cfg.Allowlist[0].String())
+	assert.True(t, cfg.Allowlist[1].Contains(netip.MustParseAddr("192.168.1.1")))
+	assert.Empty(t, cfg.Listen.Host())
+	assert.Equal(t, uint16(8080), cfg.Listen.Port())
+	assert.Equal(t, ":8080", cfg.Listen.String())
+
+	out, err := config.Dump(cfg, config.FormatJSON)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"bind_ip": "::1", "allowlist": ["10.0.0.0/8", "192.168.0.0/16"], "listen": ":8080"}`, string(out))
+}
+
+// TestNetTypesInvalid tests that malformed network values fail at load
+func TestNetTypesInvalid(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	tests := []struct {
+		env, value string
+		err        error
+	}{
+		{"BIND_IP", "300.1.1.1", config.ErrInvalidIP},
+		{"ALLOWLIST", `["10.0.0.0/33"]`, config.ErrInvalidCIDR},
+		{"LISTEN", "localhost", config.ErrInvalidHostPort},
+		{"LISTEN", "localhost:99999", config.ErrInvalidHostPort},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv(tt.env, tt.value)
+			var cfg netConfig
+			require.ErrorIs(t, config.Load(&cfg), tt.err)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Optional holds a configuration value that may be unset, so that a value explicitly
+// set to its zero value (e.g. `port: 0`) can be told apart from a missing one.
+type Optional[T any] struct {
+	value T
+	set   bool
+}
+
+// Some returns an Optional holding the given value.
+func Some[T any](v T) Optional[T] {
+	return Optional[T]{value: v, set: true}
+}
+
+// IsSet reports whether the value was provided by any source.
+func (o Optional[T]) IsSet() bool {
+	return o.set
+}
+
+// Value returns the value, or the zero value of T if it is not set.
+func (o Optional[T]) Value() T {
+	return o.value
+}
+
+// Get returns the value and whether it is set.
+func (o Optional[T]) Get() (T, bool) {
+	return o.value, o.set
+}
+
+// Or returns the value if it is set, or fallback otherwise.
+func (o Optional[T]) Or(fallback T) T {
+	if !o.set {
+		return fallback
+	}
+
+	return o.value
+}
+
+// MarshalJSON implements json.Marshaler. Unset values are marshaled as null.
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	if !o.set {
+		return []byte("null"), nil
+	}
+
+	b, err := json.Marshal(o.value)
+	if err != nil {
+		return nil, fmt.Errorf("marshal optional: %w", err)
+	}
+	return b, nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler. null leaves the value unset.
+func (o *Optional[T]) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		*o = Optional[T]{}
+		return nil
+	}
+
+	var v T
+	if err := json.Unmarshal(b, &v); err != nil {
+		return fmt.Errorf("unmarshal optional: %w", err)
+	}
+
+	*o = Some(v)
+	return nil
+}
+
+// optional is implemented by *Optional[T] to let the decoder and Dump handle it without knowing T.
+type optional interface {
+	decodeOptional(data any, decode func(in, out any) error) error
+	optionalValue() (any, bool)
+}
+
+func (o *Optional[T]) decodeOptional(data any, decode func(in, out any) error) error {
+	var v T
+	if err := decode(data, &v); err != nil {
+		return err
+	}
+
+	*o = Some(v)
+	return nil
+}
+
+func (o *Optional[T]) optionalValue() (any, bool) {
+	return o.value, o.set
+}
@@ -0,0 +1,76 @@
+package config_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type optionalConfig struct {
+	Port    config.Optional[int]           `koanf:"port"`
+	Debug   config.Optional[bool]          `koanf:"debug"`
+	Timeout config.Optional[time.Duration] `koanf:"timeout"`
+	Limits  config.Optional[struct {
+		Max int `koanf:"max"`
+	}] `koanf:"limits"`
+}
+
+// TestOptional tests telling unset values apart from zero values
+func TestOptional(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	t.Setenv("TIMEOUT", "5s")
+	yamlFile := writeTempFile(t, tmpDir, "config.yaml", `port: 0
+limits:
+  max: 10`)
+
+	var cfg optionalConfig
+	require.NoError(t, config.Load(&cfg, config.WithLocalYAML(yamlFile)))
+
+	port, ok := cfg.Port.Get()
+	assert.True(t, ok)
+	assert.Equal(t, 0, port)
+
+	assert.False(t, cfg.Debug.IsSet())
+	assert.True(t, cfg.Debug.Or(true))
+
+	assert.True(t, cfg.Timeout.IsSet())
+	assert.Equal(t, 5*time.Second, cfg.Timeout.Value())
+
+	assert.Equal(t, 10, cfg.Limits.Value().Max)
+
+	out, err := config.Dump(cfg, config.FormatJSON)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"port": 0, "debug": null, "timeout": "5s", "limits": {"max": 10}}`, string(out))
+}
+
+// TestOptionalInvalid tests that decode errors of the wrapped value are reported
+func TestOptionalInvalid(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("PORT", "not-a-number")
+
+	var cfg optionalConfig
+	require.ErrorContains(t, config.Load(&cfg), "port")
+}
+
+// TestOptionalJSON tests JSON round trip of Optional
+func TestOptionalJSON(t *testing.T) {
+	var v struct {
+		A config.Optional[int] `json:"a"`
+		B config.Optional[int] `json:"b"`
+	}
+	v.A = config.Some(0)
+
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"a": 0, "b": null}`, string(b))
+
+	v.A = config.Optional[int]{}
+	require.NoError(t, json.Unmarshal(b, &v))
+	assert.True(t, v.A.IsSet())
+	assert.False(t, v.B.IsSet())
+}
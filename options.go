@@ -1,7 +1,120 @@
 package config
 
+import (
+	"io/fs"
+	"log/slog"
+	"maps"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/knadh/koanf/v2"
+)
+
 type options struct {
-	withYaml string
+	withYaml    string
+	appName     string
+	bundle      string
+	exeDir      string
+	provenance  *Provenance
+	logger      *slog.Logger
+	aliases     map[string]string
+	migrations  map[int]Migration
+	decrypters  []decrypter
+	timeLayouts []string
+	listSep     string
+	splitSlices bool
+	sliceMerge  SliceMerge
+	merge       MergeFunc
+	lockedKeys  []string
+	delim       string
+	mapKey      func(string) string
+	tags        []string
+	decodeHooks []mapstructure.DecodeHookFunc
+	unmarshal   *koanf.UnmarshalConf
+	weakInput   bool
+	schema      []byte
+	transforms  []func(k *koanf.Koanf) error
+
+	koanfs        []*koanf.Koanf
+	sources       []Source
+	sourceTimeout time.Duration
+	backoff       Backoff
+	cacheDir      string
+	breaker       *CircuitBreaker
+	fileCache     *FileCache
+	verifiers     map[string]Verifier
+	environ       map[string]string
+	fsys          fs.FS
+	credentials   bool
+	flagSets      []func(k *koanf.Koanf, delim string) error
+
+	warnDuplicates  bool
+	requireEnv      bool
+	secretResolvers map[string]SecretResolver
+
+	overrides map[string]any
+	tenant    string
+	dryRun    bool
+	keyOrder  map[string][]string
+
+	onSourceLoaded []func(SourceInfo)
+	onLoad         []func(LoadInfo)
+}
+
+func newOptions() *options {
+	return &options{
+		withYaml:    "",
+		appName:     "",
+		bundle:      "",
+		exeDir:      "",
+		provenance:  nil,
+		logger:      slog.New(slog.DiscardHandler),
+		aliases:     map[string]string{},
+		migrations:  map[int]Migration{},
+		decrypters:  nil,
+		timeLayouts: []string{time.RFC3339Nano},
+		listSep:     defaultListSeparator,
+		splitSlices: false,
+		sliceMerge:  nil,
+		merge:       nil,
+		lockedKeys:  nil,
+		delim:       defaultKeyDelimiter,
+		mapKey:      strings.ToLower,
+		tags:        []string{tagName},
+		decodeHooks: nil,
+		unmarshal:   nil,
+		weakInput:   false,
+		schema:      nil,
+		transforms:  nil,
+
+		koanfs:        nil,
+		sources:       nil,
+		sourceTimeout: 0,
+		backoff:       nil,
+		cacheDir:      "",
+		breaker:       nil,
+		fileCache:     nil,
+		verifiers:     map[string]Verifier{},
+		environ:       nil,
+		fsys:          nil,
+		credentials:   false,
+		flagSets:      nil,
+
+		warnDuplicates:  false,
+		requireEnv:      false,
+		secretResolvers: map[string]SecretResolver{},
+
+		overrides: nil,
+		tenant:    "",
+		dryRun:    false,
+		keyOrder:  map[string][]string{},
+
+		onSourceLoaded: nil,
+		onLoad:         nil,
+	}
 }
 
 type Option func(*options)
@@ -13,9 +126,435 @@ func (o *options) apply(opts ...Option) {
 }
 
 // WithLocalYAML specifies a path to a local YAML file to load config from.
-// If the file does not exist, an error is not returned.
+// If the file does not exist, an error is not returned. A leading `~` and environment
+// variables, e.g. $HOME, in path are expanded.
+//
+// The file may contain several documents separated by `---`, merged in order, and values tagged with
+// `!include path`, `!file path` (both relative to the file), `!env NAME` and `!secret scheme:ref`.
+// Included files may reference the anchors of the including files, e.g. with `<<: *defaults`.
+// Files ending in .gz or .zst are decompressed.
 func WithLocalYAML(path string) Option {
 	return func(o *options) {
 		o.withYaml = path
 	}
 }
+
+// WithAppName loads the first existing file of $XDG_CONFIG_HOME/name/config.yaml,
+// ~/.config/name/config.yaml and /etc/name/config.yaml as the local YAML file.
+// A path set with WithLocalYAML takes precedence.
+func WithAppName(name string) Option {
+	return func(o *options) {
+		o.appName = name
+	}
+}
+
+// WithBundle specifies a path to a tar or zip archive, optionally compressed, whose YAML files are
+// loaded after the local YAML file in lexical order of their paths, e.g. conf.d/00-base.yaml
+// before conf.d/10-prod.yaml. Includes are resolved within the archive.
+// If the archive does not exist, an error is not returned. The path is expanded as in WithLocalYAML.
+func WithBundle(path string) Option {
+	return func(o *options) {
+		o.bundle = path
+	}
+}
+
+// WithExecutableRelativePaths resolves relative paths passed to options, such as WithLocalYAML,
+// against the directory of the running binary instead of the working directory, e.g. for
+// services started from / by systemd. The `.env` file is still looked up in the working directory.
+//
+// If the directory of the binary cannot be determined, paths are left relative.
+func WithExecutableRelativePaths() Option {
+	return func(o *options) {
+		o.exeDir, _ = executableDir()
+	}
+}
+
+// WithProvenance records, for every key of the effective configuration, which source set its final value.
+// The given Provenance is reset on every Load.
+func WithProvenance(p *Provenance) Option {
+	return func(o *options) {
+		o.provenance = p
+	}
+}
+
+// WithLogger specifies a logger for startup diagnostics.
+// Each loaded source is logged at debug level, and a redacted summary of the final config at info level.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) {
+		if logger == nil {
+			logger = slog.New(slog.DiscardHandler)
+		}
+		o.logger = logger
+	}
+}
+
+// OnSourceLoaded registers a callback fired after each source is loaded, before it is merged.
+// It is also fired when a source fails, with SourceInfo.Err set.
+func OnSourceLoaded(fn func(SourceInfo)) Option {
+	return func(o *options) {
+		o.onSourceLoaded = append(o.onSourceLoaded, fn)
+	}
+}
+
+// OnLoad registers a callback fired when Load completes, after the final unmarshal.
+// It is also fired when Load fails, with LoadInfo.Err set.
+func OnLoad(fn func(LoadInfo)) Option {
+	return func(o *options) {
+		o.onLoad = append(o.onLoad, fn)
+	}
+}
+
+// WithAliases maps legacy key paths to their new names, e.g. {"db.host": "database.host"}.
+// Aliases apply to every source, so the legacy environment variable DB__HOST sets database.host too.
+// Whole sections can be renamed by aliasing their prefix; a legacy section is merged into the new one
+// leaf by leaf, with the new key winning where both set the same leaf.
+func WithAliases(aliases map[string]string) Option {
+	return func(o *options) {
+		maps.Copy(o.aliases, aliases)
+	}
+}
+
+// WithMigration registers a migration of configurations with `config_version: from` to version from+1.
+// Migrations run on the raw map of each source setting `config_version`, chained until no migration
+// is registered for the version reached, before aliases apply and sources are merged.
+func WithMigration(from int, fn Migration) Option {
+	return func(o *options) {
+		o.migrations[from] = fn
+	}
+}
+
+// WithAgeIdentityFile enables decryption of values that are armored age ciphertexts
+// ("-----BEGIN AGE ENCRYPTED FILE-----") using the identities from the given file.
+// The path is expanded as in WithLocalYAML.
+func WithAgeIdentityFile(path string) Option {
+	return func(o *options) {
+		o.decrypters = append(o.decrypters, ageDecrypter(func() ([]byte, error) {
+			return readAgeIdentityFile(o.expandPath(path))()
+		}))
+	}
+}
+
+// WithAgeIdentityEnv enables decryption of values that are armored age ciphertexts
+// using the identities from the given environment variable, looked up as set with WithEnviron.
+func WithAgeIdentityEnv(name string) Option {
+	return func(o *options) {
+		o.decrypters = append(o.decrypters, ageDecrypter(readAgeIdentityEnv(name, o.lookupEnv)))
+	}
+}
+
+// WithDecryptor enables decryption of values matching the given pattern, e.g. regexp.MustCompile(EncPattern).
+// If the pattern has a capture group, only the first captured group is passed to fn.
+// This allows wiring KMS, Vault transit or custom crypto without a dedicated source.
+func WithDecryptor(pattern *regexp.Regexp, fn Decryptor) Option {
+	return func(o *options) {
+		o.decrypters = append(o.decrypters, patternDecrypter(pattern, fn))
+	}
+}
+
+// WithSecretResolver registers a resolver for YAML values tagged with `!secret scheme:ref`,
+// e.g. `!secret vault:kv/app#api_key`. The resolver is called with the reference after the scheme.
+func WithSecretResolver(scheme string, fn SecretResolver) Option {
+	return func(o *options) {
+		o.secretResolvers[scheme] = fn
+	}
+}
+
+// WithTimeLayouts specifies the layouts tried, in order, when parsing strings into time.Time fields.
+// The given layouts replace the default, time.RFC3339Nano; include it explicitly to keep accepting RFC 3339.
+func WithTimeLayouts(layouts ...string) Option {
+	return func(o *options) {
+		o.timeLayouts = layouts
+	}
+}
+
+// WithListSeparator specifies the separator used to split scalar strings into StringList fields.
+// Defaults to a comma.
+func WithListSeparator(sep string) Option {
+	return func(o *options) {
+		o.listSep = sep
+	}
+}
+
+// WithSplitSlices makes every slice field, e.g. []string or []int, accept a scalar string
+// split by the list separator, so TAGS=a,b,c works without JSON array syntax.
+func WithSplitSlices() Option {
+	return func(o *options) {
+		o.splitSlices = true
+	}
+}
+
+// WithSliceMerge specifies how lists set by several sources are combined, e.g. WithSliceMerge(SliceAppend)
+// lets an overlay extend a list of upstreams instead of replacing it. Defaults to SliceReplace.
+func WithSliceMerge(fn SliceMerge) Option {
+	return func(o *options) {
+		o.sliceMerge = fn
+	}
+}
+
+// WithMergeFunc replaces the function used to merge each source into the values of earlier sources,
+// giving full control over overlapping keys, e.g. per-key "first wins" semantics.
+// It takes precedence over WithSliceMerge; use DeepMerge for the default behavior.
+func WithMergeFunc(fn MergeFunc) Option {
+	return func(o *options) {
+		o.merge = fn
+	}
+}
+
+// WithLockedKeys prevents keys matching the given patterns, once set by a source, from being
+// overridden by later sources such as `.env` and environment variables, e.g. WithLockedKeys("security.*").
+// Patterns use path.Match syntax; a plain key also locks everything nested under it.
+func WithLockedKeys(patterns ...string) Option {
+	return func(o *options) {
+		o.lockedKeys = append(o.lockedKeys, patterns...)
+	}
+}
+
+// WithKeyDelimiter sets the delimiter separating nested key segments, "." by default.
+// Use it when key names legitimately contain dots, e.g. Kafka topic maps: with WithKeyDelimiter("/")
+// the key `topics/orders.v1/partitions` addresses the `orders.v1` map entry.
+// Key paths in provenance, aliases, locked keys and LoadInfo use the same delimiter.
+// Environment variables keep using "__" between segments.
+//
+// Key segments cannot contain the delimiter itself: koanf splits every key path on it and has no escape
+// syntax, so a path like `topics.orders\.v1.partitions` is not supported. Pick a delimiter that does not
+// occur in key names instead.
+func WithKeyDelimiter(delim string) Option {
+	return func(o *options) {
+		if delim == "" {
+			delim = defaultKeyDelimiter
+		}
+		o.delim = delim
+	}
+}
+
+// WithKeyMapper sets the function applied to each segment of keys read from `.env` and environment variables.
+// By default keys are lowercased; custom mappings let keys like "Content-Type" or camelCase
+// identifiers survive into map fields. Struct fields still match keys case-insensitively.
+func WithKeyMapper(fn func(key string) string) Option {
+	return func(o *options) {
+		if fn == nil {
+			fn = strings.ToLower
+		}
+		o.mapKey = fn
+	}
+}
+
+// WithPreserveKeyCase keeps keys read from `.env` and environment variables as they are.
+func WithPreserveKeyCase() Option {
+	return WithKeyMapper(func(key string) string { return key })
+}
+
+// WithTag sets the struct tag used to map keys to fields, `koanf` by default.
+// Fields without the tag fall back to the given tags in order, so existing API structs
+// can be reused as configuration, e.g. WithTag("koanf", "json", "yaml").
+func WithTag(tag string, fallbacks ...string) Option {
+	return func(o *options) {
+		if tag == "" {
+			tag = tagName
+		}
+		o.tags = append([]string{tag}, fallbacks...)
+	}
+}
+
+// WithDecodeHook adds mapstructure decode hooks that run before the built-in ones,
+// e.g. to decode custom types.
+func WithDecodeHook(hooks ...mapstructure.DecodeHookFunc) Option {
+	return func(o *options) {
+		o.decodeHooks = append(o.decodeHooks, hooks...)
+	}
+}
+
+// WithWeaklyTypedInput coerces strings from any source into the types of the target fields, e.g. "8080"
+// into an int and "1" into a bool, even if WithUnmarshalConf disables weak typing. Booleans also accept
+// yes, no, on and off in any case, as commonly used in environment variables.
+func WithWeaklyTypedInput() Option {
+	return func(o *options) {
+		o.weakInput = true
+	}
+}
+
+// WithUnmarshalConf overrides the configuration used to unmarshal into the target struct,
+// e.g. to enable ErrorUnused or disable WeaklyTypedInput.
+// A non-empty Tag replaces the primary struct tag. A nil DecoderConfig.DecodeHook keeps the
+// built-in hooks, a non-nil one replaces them. DecoderConfig.TagName, Result and Squash are always
+// overridden, with the primary tag, the target and true, as Dump and the other renderers rely on them.
+// Other fields are used as given, so WeaklyTypedInput must be true for environment variables to decode
+// into non-string fields, unless WithWeaklyTypedInput is used.
+func WithUnmarshalConf(conf koanf.UnmarshalConf) Option {
+	return func(o *options) {
+		if conf.Tag != "" {
+			o.tags = append([]string{conf.Tag}, o.tags[1:]...)
+		}
+		o.unmarshal = &conf
+	}
+}
+
+// WithTransform adds a function that modifies the merged configuration after all sources are loaded
+// and values are decrypted, before it is unmarshaled, e.g. to rename keys or set computed values.
+// Transforms run in the order they are added.
+func WithTransform(fn func(k *koanf.Koanf) error) Option {
+	return func(o *options) {
+		o.transforms = append(o.transforms, fn)
+	}
+}
+
+// WithSchema validates the merged configuration against the given JSON Schema, e.g. one produced by GenerateSchema,
+// before it is unmarshaled, and again with the values set by a Deriver. Violations are reported with the paths of the offending keys and wrap ErrSchemaViolation.
+func WithSchema(schema []byte) Option {
+	return func(o *options) {
+		o.schema = schema
+	}
+}
+
+// WithKoanf merges the values of an existing koanf instance, e.g. one populated with custom koanf providers,
+// before custom sources added with WithSource, so `.env` files and environment variables still override them.
+// The instance is read on every Load and not modified.
+func WithKoanf(k *koanf.Koanf) Option {
+	return func(o *options) {
+		o.koanfs = append(o.koanfs, k)
+	}
+}
+
+// WithSource adds custom configuration sources, e.g. a remote configuration server.
+func WithSource(sources ...Source) Option {
+	return func(o *options) {
+		o.sources = append(o.sources, sources...)
+	}
+}
+
+// WithGit adds a source loading the YAML file at path, or the YAML files of the directory at path
+// in lexical order, from the given ref (branch, tag or commit) of a git repository, fetched
+// on every Load with the git command, e.g. when polling with Watcher.Poll.
+// The source is named `git:<url>#<ref>:<path>`, with credentials removed from the URL.
+func WithGit(repoURL, ref, path string) Option {
+	return func(o *options) {
+		o.sources = append(o.sources, newGitSource(repoURL, ref, path))
+	}
+}
+
+// WithSourceTimeout bounds every attempt to load a custom source.
+func WithSourceTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.sourceTimeout = timeout
+	}
+}
+
+// WithRetry retries failed loads of custom sources according to the given backoff,
+// e.g. WithRetry(ExponentialBackoff(100*time.Millisecond, 5*time.Second, 5)).
+func WithRetry(backoff Backoff) Option {
+	return func(o *options) {
+		o.backoff = backoff
+	}
+}
+
+// WithSourceCache keeps the last successfully loaded configuration of every custom source
+// in the given directory, and loads from it when the source is unavailable, logging a warning
+// with the age of the cached copy. Cache files may contain secrets and are readable by the owner only.
+// The path is expanded as in WithLocalYAML.
+func WithSourceCache(dir string) Option {
+	return func(o *options) {
+		o.cacheDir = dir
+	}
+}
+
+// WithCircuitBreaker skips custom sources whose circuit in the given breaker is open.
+// Pass the same breaker to every load, e.g. via NewWatcher.
+func WithCircuitBreaker(b *CircuitBreaker) Option {
+	return func(o *options) {
+		o.breaker = b
+	}
+}
+
+// WithVerification only accepts the configuration of the named source if verify accepts its document,
+// e.g. with VerifySHA256 or VerifyEd25519. The source must implement RawSource.
+//
+// A cached copy, if WithSourceCache is used, is only written after successful verification.
+func WithVerification(source string, verify Verifier) Option {
+	return func(o *options) {
+		o.verifiers[source] = verify
+	}
+}
+
+// WithFileCache reads the YAML and `.env` files through the given cache, shared by all loads it is passed to.
+func WithFileCache(c *FileCache) Option {
+	return func(o *options) {
+		o.fileCache = c
+	}
+}
+
+// WithDuplicateKeyWarnings logs keys defined twice in the same mapping of a YAML document,
+// with their line numbers, and uses the last value. By default such documents fail to load
+// with ErrDuplicateKey.
+func WithDuplicateKeyWarnings() Option {
+	return func(o *options) {
+		o.warnDuplicates = true
+	}
+}
+
+// WithRequiredEnvTags fails loading with ErrEnvNotSet when a YAML value tagged with `!env NAME`
+// refers to an unset variable. By default such values are null.
+func WithRequiredEnvTags() Option {
+	return func(o *options) {
+		o.requireEnv = true
+	}
+}
+
+// WithEnviron uses the given variables instead of the process environment, so tests do not depend on
+// the environment they run in and can run in parallel.
+func WithEnviron(vars map[string]string) Option {
+	return func(o *options) {
+		if vars == nil {
+			vars = map[string]string{}
+		}
+		o.environ = vars
+	}
+}
+
+// WithSystemdCredentials loads the credentials passed by systemd with LoadCredential= from
+// $CREDENTIALS_DIRECTORY, after the `.env` file and before environment variables. Each file sets one key,
+// named like an environment variable: the contents of DATABASE__PASSWORD set database.password.
+// Outside of systemd, when the variable is unset, nothing is loaded.
+func WithSystemdCredentials() Option {
+	return func(o *options) {
+		o.credentials = true
+	}
+}
+
+// WithFS reads the YAML and `.env` files from fsys instead of the operating system, with paths relative
+// to the root of fsys rather than the working directory. It takes precedence over WithFileCache.
+//
+// Fields such as FileContents and ReadableFile are still checked against the operating system.
+func WithFS(fsys fs.FS) Option {
+	return func(o *options) {
+		o.fsys = fsys
+	}
+}
+
+// withOverrides sets runtime overrides of keys, applied on top of all sources.
+func withOverrides(overrides map[string]any) Option {
+	return func(o *options) {
+		o.overrides = overrides
+	}
+}
+
+// withDryRun disables OnSourceLoaded and OnLoad hooks, WithProvenance, writes to the WithSourceCache
+// directory, recording results in the WithCircuitBreaker breaker and logging the loaded configuration,
+// so a load has no side effects.
+func withDryRun() Option {
+	return func(o *options) {
+		o.dryRun = true
+		o.provenance = nil
+		o.onSourceLoaded = nil
+		o.onLoad = nil
+	}
+}
+
+// lookupEnv looks up a variable in the environment set with WithEnviron or the process environment.
+func (o *options) lookupEnv(name string) (string, bool) {
+	if o.environ != nil {
+		v, ok := o.environ[name]
+		return v, ok
+	}
+
+	return os.LookupEnv(name)
+}
@@ -0,0 +1,286 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// OrderedMap is a configuration section whose entries keep the order of the YAML file, for sections
+// where order matters, e.g. middleware chains or routing rules:
+//
+//	Middleware config.OrderedMap[MiddlewareConfig] `koanf:"middleware"`
+//
+// Entries added by other sources, e.g. environment variables, follow in lexical order. The order is known
+// for OrderedMap fields of structs, not for those nested in lists or maps.
+//
+// Marshal, Dump and Save write the entries in order, and Diff, Hash and Equal treat reordered entries as a change.
+type OrderedMap[V any] struct {
+	keys   []string
+	values map[string]V
+}
+
+// Len returns the number of entries.
+func (m OrderedMap[V]) Len() int {
+	return len(m.keys)
+}
+
+// Keys returns the keys in order.
+func (m OrderedMap[V]) Keys() []string {
+	return slices.Clone(m.keys)
+}
+
+// Get returns the value of key and whether it is set.
+func (m OrderedMap[V]) Get(key string) (V, bool) {
+	v, ok := m.values[key]
+	return v, ok
+}
+
+// All returns an iterator over the entries in order.
+func (m OrderedMap[V]) All() iter.Seq2[string, V] {
+	return func(yield func(string, V) bool) {
+		for _, key := range m.keys {
+			if !yield(key, m.values[key]) {
+				return
+			}
+		}
+	}
+}
+
+// MarshalJSON implements json.Marshaler, keeping the order of the entries.
+func (m OrderedMap[V]) MarshalJSON() ([]byte, error) {
+	keys, values := m.orderedEntries()
+	return orderedValues{keys: keys, values: values}.MarshalJSON()
+}
+
+// orderedValues is the rendered form of an OrderedMap, see dumpValue. It is encoded in the order of keys.
+type orderedValues struct {
+	keys   []string
+	values map[string]any
+}
+
+func (o orderedValues) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		k, _ := json.Marshal(key)
+		v, err := json.Marshal(o.values[key])
+		if err != nil {
+			return nil, fmt.Errorf("marshal %s: %w", key, err)
+		}
+		b.Write(k)
+		b.WriteByte(':')
+		b.Write(v)
+	}
+	b.WriteByte('}')
+
+	return b.Bytes(), nil
+}
+
+func (o orderedValues) MarshalYAML() (any, error) {
+	node := newNode(yaml.MappingNode, "!!map", "")
+	for _, key := range o.keys {
+		value := new(yaml.Node)
+		if err := value.Encode(o.values[key]); err != nil {
+			return nil, fmt.Errorf("marshal %s: %w", key, err)
+		}
+		node.Content = append(node.Content, newNode(yaml.ScalarNode, "!!str", key), value)
+	}
+
+	return node, nil
+}
+
+// unordered replaces the orderedValues in v by plain maps, recursively.
+func unordered(v any) any {
+	switch v := v.(type) {
+	case orderedValues:
+		return unordered(v.values)
+	case map[string]any:
+		for key, val := range v {
+			v[key] = unordered(val)
+		}
+		return v
+	case []any:
+		for i, val := range v {
+			v[i] = unordered(val)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// keyOrders collects the keys of the non-empty orderedValues in v by key path.
+// Like kmaps.Flatten, it does not descend into lists.
+func keyOrders(orders map[string][]string, path, delim string, v any) {
+	switch v := v.(type) {
+	case orderedValues:
+		if len(v.keys) > 0 {
+			orders[path] = v.keys
+		}
+		keyOrders(orders, path, delim, v.values)
+	case map[string]any:
+		for key, val := range v {
+			if path != "" {
+				key = path + delim + key
+			}
+			keyOrders(orders, key, delim, val)
+		}
+	}
+}
+
+// reordered reports whether the keys present in both a and b are in a different order.
+func reordered(a, b []string) bool {
+	common := func(keys, other []string) []string {
+		return slices.DeleteFunc(slices.Clone(keys), func(key string) bool { return !slices.Contains(other, key) })
+	}
+
+	return !slices.Equal(common(a, b), common(b, a))
+}
+
+// orderedMap is implemented by *OrderedMap[V] to let the decoder and Dump handle it without knowing V.
+type orderedMap interface {
+	decodeOrdered(data any, decode func(in, out any) error) error
+	orderedEntries() ([]string, map[string]any)
+	setKeyOrder(order []string)
+}
+
+func (m *OrderedMap[V]) decodeOrdered(data any, decode func(in, out any) error) error {
+	var values map[string]V
+	if err := decode(data, &values); err != nil {
+		return err
+	}
+
+	*m = OrderedMap[V]{keys: slices.Sorted(maps.Keys(values)), values: values}
+	return nil
+}
+
+func (m *OrderedMap[V]) orderedEntries() ([]string, map[string]any) {
+	values := make(map[string]any, len(m.values))
+	for key, v := range m.values {
+		values[key] = v
+	}
+
+	return m.keys, values
+}
+
+// setKeyOrder orders the keys listed in order first, the others after them in lexical order.
+func (m *OrderedMap[V]) setKeyOrder(order []string) {
+	keys := make([]string, 0, len(m.keys))
+	for _, key := range order {
+		if _, ok := m.values[key]; ok && !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	for _, key := range m.keys {
+		if !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+
+	m.keys = keys
+}
+
+// recordKeyOrder records the order of the keys of every mapping of a YAML document by key path.
+// Keys first seen in later documents or files are appended.
+func (o *options) recordKeyOrder(node *yaml.Node, path []string) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			o.recordKeyOrder(child, path)
+		}
+	case yaml.AliasNode:
+		o.recordKeyOrder(node.Alias, path)
+	case yaml.MappingNode:
+		key := strings.Join(path, o.delim)
+		for _, pair := range mappingPairs(node) {
+			if !slices.Contains(o.keyOrder[key], pair.key) {
+				o.keyOrder[key] = append(o.keyOrder[key], pair.key)
+			}
+			o.recordKeyOrder(pair.value, append(slices.Clip(path), pair.key))
+		}
+	default:
+	}
+}
+
+type yamlPair struct {
+	key   string
+	value *yaml.Node
+}
+
+// mappingPairs returns the pairs of a mapping in order, with the pairs of merge keys (`<<`) in their place.
+func mappingPairs(node *yaml.Node) []yamlPair {
+	var pairs []yamlPair
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if key.Value != yamlMergeKey {
+			pairs = append(pairs, yamlPair{key: key.Value, value: value})
+			continue
+		}
+
+		sources := []*yaml.Node{value}
+		if value.Kind == yaml.SequenceNode {
+			sources = value.Content
+		}
+		for _, src := range sources {
+			if src.Kind == yaml.AliasNode {
+				src = src.Alias
+			}
+			if src.Kind == yaml.MappingNode {
+				pairs = append(pairs, mappingPairs(src)...)
+			}
+		}
+	}
+
+	return pairs
+}
+
+// applyKeyOrder orders the OrderedMap fields of the struct v by the recorded key order.
+func applyKeyOrder(v reflect.Value, key string, options *options, depth int) {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+
+	if v.CanAddr() {
+		if m, ok := v.Addr().Interface().(orderedMap); ok {
+			m.setKeyOrder(options.keyOrder[key])
+			return
+		}
+	}
+
+	if v.Kind() != reflect.Struct || !isNested(v.Type()) || depth > maxFieldDepth {
+		return
+	}
+
+	t := v.Type()
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, squash := fieldKey(f, options.tags)
+		if name == "-" {
+			continue
+		}
+
+		path := key
+		if !squash {
+			path = strings.TrimPrefix(key+options.delim+name, options.delim)
+		}
+		applyKeyOrder(v.Field(i), path, options, depth+1)
+	}
+}
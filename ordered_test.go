@@ -0,0 +1,127 @@
+package config_test
+
+import (
+	"encoding/json"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type middleware struct {
+	Enabled bool `koanf:"enabled"`
+}
+
+type orderedConfig struct {
+	HTTP struct {
+		Middleware config.OrderedMap[middleware] `koanf:"middleware"`
+	} `koanf:"http"`
+	Routes config.OrderedMap[string] `koanf:"routes"`
+}
+
+// TestOrderedMap tests that entries keep the order of the YAML file
+func TestOrderedMap(t *testing.T) {
+	files := fstest.MapFS{
+		"config.yaml": {Data: []byte(`
+defaults: &defaults
+  recover:
+    enabled: true
+  logging:
+    enabled: true
+http:
+  middleware:
+    tracing:
+      enabled: true
+    <<: *defaults
+    auth:
+      enabled: false
+routes: !include routes.yaml
+`)},
+		"routes.yaml": {Data: []byte("/z: z\n/a: a\n/m: m\n")},
+	}
+
+	var c orderedConfig
+	require.NoError(t, loadYAML(t, &c, files, config.WithEnviron(map[string]string{
+		"HTTP__MIDDLEWARE__CORS__ENABLED": "true",
+		"HTTP__MIDDLEWARE__AUTH__ENABLED": "true",
+	})))
+
+	assert.Equal(t, []string{"tracing", "recover", "logging", "auth", "cors"}, c.HTTP.Middleware.Keys())
+	auth, ok := c.HTTP.Middleware.Get("auth")
+	require.True(t, ok)
+	assert.True(t, auth.Enabled)
+
+	var routes []string
+	for key, target := range c.Routes.All() {
+		routes = append(routes, key+"="+target)
+	}
+	assert.Equal(t, []string{"/z=z", "/a=a", "/m=m"}, routes)
+
+	b, err := json.Marshal(c.Routes)
+	require.NoError(t, err)
+	assert.Equal(t, `{"/z":"z","/a":"a","/m":"m"}`, string(b))
+
+	dump, err := config.Dump(&c, config.FormatJSON)
+	require.NoError(t, err)
+	assert.Contains(t, string(dump), `"cors"`)
+
+	schema, err := config.GenerateSchema[orderedConfig]()
+	require.NoError(t, err)
+	assert.Contains(t, string(schema), `"additionalProperties": {
+        "type": "string"`)
+}
+
+// TestOrderedMapReordered tests that reordering entries is a change of the configuration
+func TestOrderedMapReordered(t *testing.T) {
+	load := func(routes string) orderedConfig {
+		var c orderedConfig
+		require.NoError(t, loadYAML(t, &c, fstest.MapFS{"config.yaml": {Data: []byte("routes:\n" + routes)}}))
+		return c
+	}
+	a := load("  /z: z\n  /a: a\n")
+	b := load("  /a: a\n  /z: z\n")
+
+	changes, err := config.Diff(a, b)
+	require.NoError(t, err)
+	assert.Equal(t, []config.Change{{Key: "routes", Old: []string{"/z", "/a"}, New: []string{"/a", "/z"}, Secret: false}}, changes)
+
+	changes, err = config.Diff(a, load("  /z: z\n  /b: b\n  /a: a\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []config.Change{{Key: "routes./b", Old: nil, New: "b", Secret: false}}, changes)
+
+	hashA, err := config.Hash(&a)
+	require.NoError(t, err)
+	hashB, err := config.Hash(&b)
+	require.NoError(t, err)
+	assert.NotEqual(t, hashA, hashB)
+
+	assert.False(t, config.Equal(a, b))
+	assert.True(t, config.Equal(a, load("  /z: z\n  /a: a\n")))
+
+	out, err := config.Marshal(&a, config.FormatYAML)
+	require.NoError(t, err)
+	assert.Contains(t, string(out), "routes:\n    /z: z\n    /a: a\n")
+
+	out, err = config.Marshal(&a, config.FormatJSON)
+	require.NoError(t, err)
+	assert.Contains(t, string(out), "\"routes\": {\n    \"/z\": \"z\",\n    \"/a\": \"a\"\n  }")
+}
+
+// TestOrderedMapWatcher tests that watchers report reordered entries
+func TestOrderedMapWatcher(t *testing.T) {
+	files := fstest.MapFS{"config.yaml": {Data: []byte("routes:\n  /z: z\n  /a: a\n")}}
+	w, err := config.NewWatcher[orderedConfig](t.Context(),
+		config.WithFS(files), config.WithLocalYAML("config.yaml"), config.WithEnviron(nil))
+	require.NoError(t, err)
+
+	var changes []config.Change
+	w.OnChange(func(e config.ChangeEvent[orderedConfig]) { changes = e.Changes })
+
+	files["config.yaml"] = &fstest.MapFile{Data: []byte("routes:\n  /a: a\n  /z: z\n")}
+	require.NoError(t, w.Reload(t.Context()))
+	require.Len(t, changes, 1)
+	assert.Equal(t, "routes", changes[0].Key)
+	assert.Equal(t, []string{"/a", "/z"}, w.Get().Routes.Keys())
+}
@@ -0,0 +1,74 @@
+// Package otelconfig traces configuration loads with OpenTelemetry.
+package otelconfig
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-core-fx/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/go-core-fx/config/otelconfig"
+
+// Tracer traces configuration loads.
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+// NewTracer creates a Tracer using the given provider, or the global one if it is nil.
+func NewTracer(tp trace.TracerProvider) *Tracer {
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+
+	return &Tracer{tracer: tp.Tracer(instrumentationName)}
+}
+
+// Load is like config.LoadContext, recording a "config.Load" span with a child span per source.
+func Load[T any](ctx context.Context, t *Tracer, c *T, opts ...config.Option) error {
+	ctx, span := t.tracer.Start(ctx, "config.Load")
+	defer span.End()
+
+	opts = append(opts,
+		config.OnSourceLoaded(func(src config.SourceInfo) { t.source(ctx, src) }),
+		config.OnLoad(func(info config.LoadInfo) { loaded(span, info) }),
+	)
+
+	return config.LoadContext(ctx, c, opts...)
+}
+
+// source records a span for a loaded or failed source, ending now and lasting its load duration.
+func (t *Tracer) source(ctx context.Context, src config.SourceInfo) {
+	end := time.Now()
+	_, span := t.tracer.Start(ctx, "config.source "+src.Name,
+		trace.WithTimestamp(end.Add(-src.Duration)),
+		trace.WithAttributes(
+			attribute.String("config.source.name", src.Name),
+			attribute.String("config.source.location", src.Location),
+			attribute.Int("config.source.keys", len(src.Keys)),
+			attribute.Int("config.source.bytes", src.Bytes),
+		),
+	)
+	if src.Err != nil {
+		span.RecordError(src.Err)
+		span.SetStatus(codes.Error, src.Err.Error())
+	}
+	span.End(trace.WithTimestamp(end))
+}
+
+func loaded(span trace.Span, info config.LoadInfo) {
+	span.SetAttributes(
+		attribute.Int("config.sources", len(info.Sources)),
+		attribute.Int("config.keys", len(info.Keys)),
+		attribute.String("config.hash", info.Hash),
+	)
+
+	if info.Err != nil {
+		span.RecordError(info.Err)
+		span.SetStatus(codes.Error, info.Err.Error())
+	}
+}
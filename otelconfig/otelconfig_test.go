@@ -0,0 +1,127 @@
+package otelconfig_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-core-fx/config"
+	"github.com/go-core-fx/config/otelconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type testConfig struct {
+	Host string `koanf:"host"`
+}
+
+func attrs(s sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	m := map[attribute.Key]attribute.Value{}
+	for _, kv := range s.Attributes() {
+		m[kv.Key] = kv.Value
+	}
+	return m
+}
+
+// TestLoad tests that Load records a span per source under a Load span
+func TestLoad(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	content := "host: localhost\n"
+	path := filepath.Join(tmpDir, "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+	rec := tracetest.NewSpanRecorder()
+	tracer := otelconfig.NewTracer(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+
+	var cfg testConfig
+	require.NoError(t, otelconfig.Load(context.Background(), tracer, &cfg, config.WithLocalYAML(path)))
+	assert.Equal(t, "localhost", cfg.Host)
+
+	spans := rec.Ended()
+	require.Len(t, spans, 4)
+
+	root := spans[3]
+	assert.Equal(t, "config.Load", root.Name())
+	assert.Equal(t, int64(3), attrs(root)["config.sources"].AsInt64())
+
+	yamlSpan := spans[0]
+	assert.Equal(t, "config.source yaml", yamlSpan.Name())
+	assert.Equal(t, root.SpanContext().SpanID(), yamlSpan.Parent().SpanID())
+	assert.Equal(t, path, attrs(yamlSpan)["config.source.location"].AsString())
+	assert.Equal(t, int64(len(content)), attrs(yamlSpan)["config.source.bytes"].AsInt64())
+	assert.False(t, yamlSpan.StartTime().After(yamlSpan.EndTime()))
+
+	assert.Equal(t, "config.source dotenv", spans[1].Name())
+	assert.Equal(t, "config.source env", spans[2].Name())
+}
+
+// TestLoadError tests that load errors are recorded on the Load span
+func TestLoadError(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	path := filepath.Join(tmpDir, "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("host: [\n"), 0o644))
+
+	rec := tracetest.NewSpanRecorder()
+	tracer := otelconfig.NewTracer(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+
+	var cfg testConfig
+	require.Error(t, otelconfig.Load(context.Background(), tracer, &cfg, config.WithLocalYAML(path)))
+
+	spans := rec.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "config.source yaml", spans[0].Name())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, "config.Load", spans[1].Name())
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+}
+
+// rawSource returns a fixed YAML document.
+type rawSource struct {
+	data string
+}
+
+func (s rawSource) Name() string { return "remote" }
+
+func (s rawSource) Load(ctx context.Context) (map[string]any, error) {
+	doc, err := s.LoadRaw(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return doc.Parse()
+}
+
+func (s rawSource) LoadRaw(context.Context) (config.Document, error) {
+	return config.Document{Data: []byte(s.data), Signature: nil}, nil
+}
+
+// TestLoadRawSource tests that the size of the document of a RawSource is recorded
+func TestLoadRawSource(t *testing.T) {
+	t.Chdir(t.TempDir())
+	content := "host: remote\n"
+
+	rec := tracetest.NewSpanRecorder()
+	tracer := otelconfig.NewTracer(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+
+	var cfg testConfig
+	require.NoError(t, otelconfig.Load(context.Background(), tracer, &cfg,
+		config.WithSource(rawSource{data: content}),
+		config.WithEnviron(nil),
+	))
+	assert.Equal(t, "remote", cfg.Host)
+
+	var found bool
+	for _, s := range rec.Ended() {
+		if s.Name() == "config.source remote" {
+			found = true
+			assert.Equal(t, int64(len(content)), attrs(s)["config.source.bytes"].AsInt64())
+		}
+	}
+	assert.True(t, found)
+}
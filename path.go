@@ -0,0 +1,138 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+var ErrInvalidPath = errors.New("invalid path")
+
+var (
+	errNotRegularFile = errors.New("not a regular file")
+	errNotDir         = errors.New("not a directory")
+	errNotWritable    = errors.New("not writable")
+)
+
+// ExistingPath is a path to a file or directory that must exist when the configuration is loaded.
+type ExistingPath struct {
+	path string
+}
+
+// Path returns the path.
+func (p ExistingPath) Path() string {
+	return p.path
+}
+
+// String returns the path.
+func (p ExistingPath) String() string {
+	return p.path
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (p ExistingPath) MarshalText() ([]byte, error) {
+	return []byte(p.path), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (p *ExistingPath) UnmarshalText(text []byte) error {
+	return unmarshalPath(&p.path, text, func(path string) error {
+		_, err := os.Stat(path)
+		return err
+	})
+}
+
+// ReadableFile is a path to a regular file that must be readable when the configuration is loaded.
+type ReadableFile struct {
+	path string
+}
+
+// Path returns the path.
+func (p ReadableFile) Path() string {
+	return p.path
+}
+
+// String returns the path.
+func (p ReadableFile) String() string {
+	return p.path
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (p ReadableFile) MarshalText() ([]byte, error) {
+	return []byte(p.path), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (p *ReadableFile) UnmarshalText(text []byte) error {
+	return unmarshalPath(&p.path, text, func(path string) error {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		info, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return errNotRegularFile
+		}
+
+		return nil
+	})
+}
+
+// WritableDir is a path to a directory that must be writable when the configuration is loaded.
+type WritableDir struct {
+	path string
+}
+
+// Path returns the path.
+func (p WritableDir) Path() string {
+	return p.path
+}
+
+// String returns the path.
+func (p WritableDir) String() string {
+	return p.path
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (p WritableDir) MarshalText() ([]byte, error) {
+	return []byte(p.path), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (p *WritableDir) UnmarshalText(text []byte) error {
+	return unmarshalPath(&p.path, text, func(path string) error {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return errNotDir
+		}
+
+		// Permission bits do not account for ACLs, read-only mounts or the effective user, so probe instead.
+		f, err := os.CreateTemp(path, ".write-probe-*")
+		if err != nil {
+			return fmt.Errorf("%w: %w", errNotWritable, err)
+		}
+		name := f.Name()
+		_ = f.Close()
+		return os.Remove(name)
+	})
+}
+
+func unmarshalPath(dst *string, text []byte, check func(path string) error) error {
+	path := string(text)
+	if path != "" {
+		if err := check(path); err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrInvalidPath, path, err)
+		}
+	}
+
+	*dst = path
+	return nil
+}
@@ -0,0 +1,67 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type pathConfig struct {
+	Existing config.ExistingPath `koanf:"existing"`
+	Readable config.ReadableFile `koanf:"readable"`
+	DataDir  config.WritableDir  `koanf:"data_dir"`
+}
+
+// TestPathTypes tests validation of path types
+func TestPathTypes(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	file := writeTempFile(t, tmpDir, "file.txt", "data")
+	dataDir := filepath.Join(tmpDir, "data")
+	require.NoError(t, os.Mkdir(dataDir, 0o755))
+
+	t.Setenv("EXISTING", tmpDir)
+	t.Setenv("READABLE", file)
+	t.Setenv("DATA_DIR", dataDir)
+
+	var cfg pathConfig
+	require.NoError(t, config.Load(&cfg))
+	assert.Equal(t, tmpDir, cfg.Existing.Path())
+	assert.Equal(t, file, cfg.Readable.Path())
+	assert.Equal(t, dataDir, cfg.DataDir.Path())
+
+	entries, err := os.ReadDir(dataDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "write probe must be removed")
+}
+
+// TestPathTypesInvalid tests that misconfigured paths fail at load
+func TestPathTypesInvalid(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	file := writeTempFile(t, tmpDir, "file.txt", "data")
+	missing := filepath.Join(tmpDir, "missing")
+
+	tests := []struct {
+		env, value string
+	}{
+		{"EXISTING", missing},
+		{"READABLE", missing},
+		{"READABLE", tmpDir},
+		{"DATA_DIR", missing},
+		{"DATA_DIR", file},
+	}
+	for _, tt := range tests {
+		t.Run(tt.env+"="+filepath.Base(tt.value), func(t *testing.T) {
+			t.Setenv(tt.env, tt.value)
+			var cfg pathConfig
+			err := config.Load(&cfg)
+			require.ErrorIs(t, err, config.ErrInvalidPath)
+			assert.ErrorContains(t, err, tt.value)
+		})
+	}
+}
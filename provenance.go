@@ -0,0 +1,113 @@
+package config
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Built-in source names reported in Origin.Source.
+const (
+	SourceYAML        = "yaml"
+	SourceBundle      = "bundle"
+	SourceKoanf       = "koanf"
+	SourceDotenv      = "dotenv"
+	SourceCredentials = "credentials"
+	SourceEnv         = "env"
+	// SourceFlags is the source of command-line flags registered with RegisterFlags.
+	SourceFlags = "flags"
+	// SourceRuntime is the source of overrides set with Watcher.Set.
+	SourceRuntime = "runtime"
+	// SourceTest is the source of overrides set with configtest.Override.
+	SourceTest = "test"
+)
+
+// Origin describes where a configuration value came from.
+type Origin struct {
+	// Source is the name of the source, e.g. SourceYAML or SourceEnv.
+	Source string
+	// Location is the file path for file-based sources or the variable name for environment variables.
+	Location string
+}
+
+// String returns a human-readable representation of the origin.
+func (o Origin) String() string {
+	if o.Location == "" {
+		return o.Source
+	}
+
+	return o.Source + " (" + o.Location + ")"
+}
+
+// Provenance maps each key of the effective configuration to the source that set its final value.
+type Provenance map[string]Origin
+
+// Lookup returns the origin of the given key.
+func (p Provenance) Lookup(key string) (Origin, bool) {
+	o, ok := p[key]
+	return o, ok
+}
+
+// Explain returns a human-readable description of where the value of the given key came from.
+func (p Provenance) Explain(key string) string {
+	o, ok := p[key]
+	if !ok {
+		return key + " is not set by any source"
+	}
+
+	return key + " is set by " + o.String()
+}
+
+// Keys returns all tracked keys in sorted order.
+func (p Provenance) Keys() []string {
+	return slices.Sorted(maps.Keys(p))
+}
+
+func track(p Provenance, keys []string, src layer, delim string) {
+	for _, key := range keys {
+		o := Origin{Source: src.name, Location: src.location}
+		if src.vars != nil {
+			o.Location = varName(key, src.vars, delim)
+		}
+		p[key] = o
+	}
+}
+
+// aliasVars renames the keys of vars, see layer.vars, as applyAliases renames the keys they set.
+func aliasVars(vars, aliases map[string]string, delim string) map[string]string {
+	if vars == nil {
+		return nil
+	}
+
+	for _, from := range slices.Sorted(maps.Keys(aliases)) {
+		renamed := make(map[string]string, len(vars))
+		for key, name := range vars {
+			if key == from || strings.HasPrefix(key, from+delim) {
+				key = aliases[from] + key[len(from):]
+			}
+			renamed[key] = name
+		}
+		vars = renamed
+	}
+
+	return vars
+}
+
+// varName returns the variable that set key: the variable of the key itself or, for values
+// decoded from JSON, of its parent. Keys are matched ignoring case, as matchKeyCase renames them.
+func varName(key string, vars map[string]string, delim string) string {
+	segments := strings.Split(key, delim)
+	for n := len(segments); n > 0; n-- {
+		prefix := strings.Join(segments[:n], delim)
+		if name, ok := vars[prefix]; ok {
+			return name
+		}
+		for k, name := range vars {
+			if strings.EqualFold(k, prefix) {
+				return name
+			}
+		}
+	}
+
+	return strings.ToUpper(strings.ReplaceAll(key, delim, envDelimiter))
+}
@@ -0,0 +1,74 @@
+package config_test
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestProvenance tests tracking of the source that set each key
+func TestProvenance(t *testing.T) {
+	t.Setenv("DATABASE__HOST", "env-host")
+
+	tmpDir := t.TempDir()
+	withDotEnv(t, tmpDir, `DATABASE__PORT=5433`)
+	yamlFile := writeTempFile(t, tmpDir, "config.yaml", `database:
+  host: yaml-host
+  port: 3306
+  username: yaml-user
+server:
+  port: 9090`)
+
+	var cfg TestConfig
+	var p config.Provenance
+	err := config.Load(&cfg, config.WithLocalYAML(yamlFile), config.WithProvenance(&p))
+	require.NoError(t, err)
+
+	host, ok := p.Lookup("database.host")
+	require.True(t, ok)
+	assert.Equal(t, config.Origin{Source: config.SourceEnv, Location: "DATABASE__HOST"}, host)
+
+	port, ok := p.Lookup("database.port")
+	require.True(t, ok)
+	assert.Equal(t, config.SourceDotenv, port.Source)
+
+	assert.Equal(t, "database.username is set by yaml ("+yamlFile+")", p.Explain("database.username"))
+	assert.Equal(t, "database.password is not set by any source", p.Explain("database.password"))
+	assert.Contains(t, p.Keys(), "server.port")
+}
+
+// TestProvenanceEnvNames tests that environment variables are reported by their actual names
+func TestProvenanceEnvNames(t *testing.T) {
+	files := fstest.MapFS{"config.yaml": {Data: []byte("feature_flags:\n  NewCheckout: false\n")}}
+
+	var p config.Provenance
+	var c TestConfig
+	require.NoError(t, loadYAML(t, &c, files,
+		config.WithEnviron(map[string]string{
+			"Db__Host":                   "legacy",
+			"database__port":             "5433",
+			"FEATURE_FLAGS__NEWCHECKOUT": "true",
+			"APP_SERVER":                 `{"port": 8080}`,
+		}),
+		config.WithKeyMapper(func(s string) string { return strings.ToLower(strings.TrimPrefix(s, "APP_")) }),
+		config.WithAliases(map[string]string{"db": "database"}),
+		config.WithProvenance(&p),
+	))
+	assert.Equal(t, "legacy", c.Database.Host)
+	assert.True(t, c.FeatureFlags["NewCheckout"])
+
+	for key, name := range map[string]string{
+		"database.host":             "Db__Host",
+		"database.port":             "database__port",
+		"feature_flags.NewCheckout": "FEATURE_FLAGS__NEWCHECKOUT",
+		"server.port":               "APP_SERVER",
+	} {
+		o, ok := p.Lookup(key)
+		require.True(t, ok, key)
+		assert.Equal(t, config.Origin{Source: config.SourceEnv, Location: name}, o, key)
+	}
+}
@@ -0,0 +1,112 @@
+//go:build windows
+
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// RegistrySource is a Source reading the values and subkeys of a Windows registry key,
+// e.g. settings managed by group policy under HKLM\SOFTWARE\Policies\Vendor\App.
+//
+// Subkeys become nested maps. Names are lowercased, so a value LogLevel under the subkey Server
+// sets the key server.loglevel. Strings are expanded, multi-strings become lists and
+// DWORD and QWORD values integers.
+type RegistrySource struct {
+	root registry.Key
+	path string
+}
+
+// NewRegistrySource creates a RegistrySource reading the key at path under root, e.g. registry.LOCAL_MACHINE.
+func NewRegistrySource(root registry.Key, path string) *RegistrySource {
+	return &RegistrySource{root: root, path: path}
+}
+
+// Name returns "registry".
+func (s *RegistrySource) Name() string {
+	return "registry"
+}
+
+// Load reads the key. A missing key is an empty configuration.
+func (s *RegistrySource) Load(context.Context) (map[string]any, error) {
+	m, err := readRegistryKey(s.root, s.path)
+	if errors.Is(err, registry.ErrNotExist) {
+		return map[string]any{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read registry key %s: %w", s.path, err)
+	}
+
+	return m, nil
+}
+
+func readRegistryKey(root registry.Key, path string) (map[string]any, error) {
+	k, err := registry.OpenKey(root, path, registry.READ)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // wrapped by the caller
+	}
+	defer k.Close()
+
+	m := map[string]any{}
+
+	names, err := k.ReadValueNames(0)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // wrapped by the caller
+	}
+	for _, name := range names {
+		v, err := readRegistryValue(k, name)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		if v != nil {
+			m[strings.ToLower(name)] = v
+		}
+	}
+
+	subkeys, err := k.ReadSubKeyNames(0)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // wrapped by the caller
+	}
+	for _, name := range subkeys {
+		sub, err := readRegistryKey(root, path+`\`+name)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		m[strings.ToLower(name)] = sub
+	}
+
+	return m, nil
+}
+
+// readRegistryValue returns the value, or nil for unsupported types such as REG_BINARY.
+func readRegistryValue(k registry.Key, name string) (any, error) {
+	_, typ, err := k.GetValue(name, nil)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // wrapped by the caller
+	}
+
+	switch typ {
+	case registry.SZ, registry.EXPAND_SZ:
+		s, _, err := k.GetStringValue(name)
+		if err != nil {
+			return nil, err //nolint:wrapcheck // wrapped by the caller
+		}
+		if typ == registry.EXPAND_SZ {
+			return registry.ExpandString(s) //nolint:wrapcheck // wrapped by the caller
+		}
+		return s, nil
+	case registry.MULTI_SZ:
+		list, _, err := k.GetStringsValue(name)
+		return list, err //nolint:wrapcheck // wrapped by the caller
+	case registry.DWORD, registry.QWORD:
+		n, _, err := k.GetIntegerValue(name)
+		return n, err //nolint:wrapcheck // wrapped by the caller
+	default:
+		return nil, nil
+	}
+}
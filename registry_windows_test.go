@@ -0,0 +1,33 @@
+//go:build windows
+
+package config_test
+
+import (
+	"testing"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/windows/registry"
+)
+
+// TestRegistrySource tests loading values and subkeys of a registry key
+func TestRegistrySource(t *testing.T) {
+	t.Chdir(t.TempDir())
+	path := `Software\go-core-fx-config-test`
+
+	k, _, err := registry.CreateKey(registry.CURRENT_USER, path+`\Database`, registry.ALL_ACCESS)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = registry.DeleteKey(registry.CURRENT_USER, path+`\Database`)
+		_ = registry.DeleteKey(registry.CURRENT_USER, path)
+	})
+	require.NoError(t, k.SetStringValue("Host", "registry"))
+	require.NoError(t, k.SetDWordValue("Port", 5432))
+	require.NoError(t, k.Close())
+
+	var c TestConfig
+	require.NoError(t, config.Load(&c, config.WithSource(config.NewRegistrySource(registry.CURRENT_USER, path))))
+	assert.Equal(t, "registry", c.Database.Host)
+	assert.Equal(t, 5432, c.Database.Port)
+}
@@ -0,0 +1,380 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/knadh/koanf/v2"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+const (
+	schemaDraft    = "https://json-schema.org/draft/2020-12/schema"
+	schemaResource = "schema.json"
+)
+
+var ErrSchemaViolation = errors.New("schema violation")
+
+// GenerateSchema returns a JSON Schema describing the YAML representation of the configuration struct T,
+// so editors can provide completion and validation for configuration files.
+//
+// Field descriptions are taken from the `desc` tag, allowed values from Enum types and the `oneof` tag.
+// Fields tagged with `deprecated` are marked as deprecated, secrets as write-only.
+func GenerateSchema[T any](opts ...MarshalOption) ([]byte, error) {
+	t := indirect(reflect.TypeFor[T]())
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedTarget, t)
+	}
+
+	s := typeSchema(t, 0, newMarshalOptions(opts).tags)
+	s["$schema"] = schemaDraft
+	if t.Name() != "" {
+		s["title"] = t.Name()
+	}
+
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshal schema: %w", err)
+	}
+
+	return append(b, '\n'), nil
+}
+
+//nolint:cyclop // a single switch over kinds reads better than several helpers
+func typeSchema(t reflect.Type, depth int, tags []string) map[string]any {
+	t = indirect(t)
+	if depth > maxFieldDepth {
+		return map[string]any{}
+	}
+
+	if s, ok := knownTypeSchema(t, depth, tags); ok {
+		return s
+	}
+
+	var s map[string]any
+	switch t.Kind() {
+	case reflect.Struct:
+		props := map[string]any{}
+		structSchema(props, t, depth, tags)
+		s = map[string]any{"type": "object", "properties": props}
+	case reflect.Map:
+		s = map[string]any{"type": "object", "additionalProperties": typeSchema(t.Elem(), depth+1, tags)}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			s = map[string]any{"type": "string"}
+			break
+		}
+		s = map[string]any{"type": "array", "items": typeSchema(t.Elem(), depth+1, tags)}
+	case reflect.String:
+		s = map[string]any{"type": "string"}
+	case reflect.Bool:
+		s = map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		s = map[string]any{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		s = map[string]any{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		s = map[string]any{"type": "number"}
+	default:
+		s = map[string]any{}
+	}
+
+	if e, ok := reflect.Zero(t).Interface().(Enum); ok {
+		s["enum"] = e.Values()
+	}
+
+	return s
+}
+
+// knownTypeSchema returns the schema of types whose YAML representation differs from their Go kind.
+func knownTypeSchema(t reflect.Type, depth int, tags []string) (map[string]any, bool) {
+	p := reflect.PointerTo(t)
+	switch {
+	case p.Implements(reflect.TypeFor[optional]()):
+		f, _ := t.FieldByName("value")
+		return typeSchema(f.Type, depth+1, tags), true
+	case p.Implements(reflect.TypeFor[orderedMap]()):
+		f, _ := t.FieldByName("values")
+		return map[string]any{"type": "object", "additionalProperties": typeSchema(f.Type.Elem(), depth+1, tags)}, true
+	case t == reflect.TypeFor[Secret]():
+		return map[string]any{"type": "string", "writeOnly": true}, true
+	case t == reflect.TypeFor[StringList]():
+		return map[string]any{"type": []string{"string", "array"}, "items": map[string]any{"type": "string"}}, true
+	case t == reflect.TypeFor[time.Time]():
+		return map[string]any{"type": "string", "format": "date-time"}, true
+	case t == reflect.TypeFor[Date]():
+		return map[string]any{"type": "string", "format": "date"}, true
+	case t == reflect.TypeFor[URL]():
+		return map[string]any{"type": "string", "format": "uri"}, true
+	case t == reflect.TypeFor[time.Duration](), p.Implements(reflect.TypeFor[encoding.TextUnmarshaler]()):
+		s := map[string]any{"type": "string"}
+		if e, ok := reflect.Zero(t).Interface().(Enum); ok {
+			s["enum"] = e.Values()
+		}
+		return s, true
+	}
+
+	return nil, false
+}
+
+func structSchema(props map[string]any, t reflect.Type, depth int, tags []string) {
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, squash := fieldKey(f, tags)
+		if name == "-" {
+			continue
+		}
+
+		if squash && indirect(f.Type).Kind() == reflect.Struct {
+			structSchema(props, indirect(f.Type), depth+1, tags)
+			continue
+		}
+
+		s := typeSchema(f.Type, depth+1, tags)
+		if desc, ok := f.Tag.Lookup(descTag); ok {
+			s["description"] = desc
+		}
+		if oneof, ok := f.Tag.Lookup("oneof"); ok {
+			s["enum"] = strings.Split(oneof, ",")
+		}
+		if _, ok := f.Tag.Lookup("deprecated"); ok {
+			s["deprecated"] = true
+		}
+		if f.Tag.Get("secret") == "true" {
+			s["writeOnly"] = true
+		}
+
+		props[name] = s
+	}
+}
+
+// ValidateFile validates the YAML file at the given path against the given JSON Schema.
+func ValidateFile(path string, schema []byte) error {
+	options := newOptions()
+	k := koanf.New(defaultKeyDelimiter)
+	if err := k.Load(options.fileProvider(path), options.yamlParser(context.Background(), path)); err != nil {
+		return fmt.Errorf("load yaml: %w", err)
+	}
+
+	return validateSchema(schema, k.Raw(), nil)
+}
+
+// schemaNumber matches numbers reported by schema violations, e.g. "maximum: got 1,234, want 10".
+var schemaNumber = regexp.MustCompile(`got -?[\d,.]+(e[+-]?\d+)?, want`)
+
+// pointerEscaper escapes keys in JSON pointers.
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// validateConfig validates the raw merged configuration to be unmarshaled into the target c,
+// so keys that do not map to a field are reported and unset fields are absent. Strings, e.g. from environment variables, are
+// converted to the numbers and booleans of the fields they set, and values of secrets are masked
+// in the reported violations.
+func validateConfig(schema []byte, raw map[string]any, c any, tags []string) error {
+	secrets := map[string]string{}
+	data := schemaValue(raw, reflect.TypeOf(c), false, "", tags, secrets)
+
+	return validateSchema(schema, data, secrets)
+}
+
+// overlayDerived sets the values of after that differ from before, i.e. those set by a Deriver,
+// in raw, and reports whether there were any.
+func overlayDerived(raw, before, after map[string]any) bool {
+	changed := false
+	for key, v := range after {
+		if sub, ok := v.(map[string]any); ok {
+			if prev, ok := before[key].(map[string]any); ok {
+				rsub, ok := raw[key].(map[string]any)
+				if !ok {
+					rsub = map[string]any{}
+				}
+				if overlayDerived(rsub, prev, sub) {
+					raw[key] = rsub
+					changed = true
+				}
+				continue
+			}
+		}
+		if !reflect.DeepEqual(v, before[key]) {
+			raw[key] = v
+			changed = true
+		}
+	}
+
+	return changed
+}
+
+// schemaValue converts the raw value v set for a field of type t as unmarshaling would, for validation.
+// Values set for secrets are collected in secrets by their JSON pointer.
+//
+//nolint:cyclop // a single switch over kinds reads better than several helpers
+func schemaValue(v any, t reflect.Type, secret bool, ptr string, tags []string, secrets map[string]string) any {
+	t = indirect(t)
+	p := reflect.PointerTo(t)
+	if p.Implements(reflect.TypeFor[optional]()) {
+		f, _ := t.FieldByName("value")
+		t = indirect(f.Type)
+		p = reflect.PointerTo(t)
+	}
+
+	if secret || t == reflect.TypeFor[Secret]() {
+		if _, ok := v.(map[string]any); !ok && v != nil {
+			secrets[ptr] = fmt.Sprint(v)
+		}
+	}
+
+	switch m := v.(type) {
+	case map[string]any:
+		switch {
+		case p.Implements(reflect.TypeFor[orderedMap]()):
+			f, _ := t.FieldByName("values")
+			for key, val := range m {
+				m[key] = schemaValue(val, f.Type.Elem(), secret, ptr+"/"+pointerEscaper.Replace(key), tags, secrets)
+			}
+		case isNested(t):
+			schemaFields(m, t, secret, ptr, tags, secrets)
+		case t.Kind() == reflect.Map:
+			for key, val := range m {
+				m[key] = schemaValue(val, t.Elem(), secret, ptr+"/"+pointerEscaper.Replace(key), tags, secrets)
+			}
+		}
+		return m
+	case []any:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			for i, val := range m {
+				m[i] = schemaValue(val, t.Elem(), secret, ptr+"/"+strconv.Itoa(i), tags, secrets)
+			}
+		}
+		return m
+	case string:
+		return parseScalar(m, t)
+	default:
+		return v
+	}
+}
+
+// schemaFields converts the values of m set for the fields of the struct type t, see schemaValue.
+func schemaFields(m map[string]any, t reflect.Type, secret bool, ptr string, tags []string, secrets map[string]string) {
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, squash := fieldKey(f, tags)
+		if name == "-" {
+			continue
+		}
+
+		fieldSecret := secret || f.Tag.Get("secret") == "true"
+		if squash && indirect(f.Type).Kind() == reflect.Struct {
+			schemaFields(m, indirect(f.Type), fieldSecret, ptr, tags, secrets)
+			continue
+		}
+
+		key := name
+		if _, ok := m[key]; !ok {
+			if key, ok = findFold(m, name); !ok {
+				continue
+			}
+		}
+		m[key] = schemaValue(m[key], f.Type, fieldSecret, ptr+"/"+pointerEscaper.Replace(key), tags, secrets)
+	}
+}
+
+// parseScalar converts s to a boolean or number if t is of that kind and s parses, as WeaklyTypedInput does.
+func parseScalar(s string, t reflect.Type) any {
+	if t == reflect.TypeFor[time.Duration]() || reflect.PointerTo(t).Implements(reflect.TypeFor[encoding.TextUnmarshaler]()) {
+		return s
+	}
+
+	var v any
+	var err error
+	switch t.Kind() {
+	case reflect.Bool:
+		v, err = strconv.ParseBool(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, err = strconv.ParseInt(s, 0, t.Bits())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v, err = strconv.ParseUint(s, 0, t.Bits())
+	case reflect.Float32, reflect.Float64:
+		v, err = strconv.ParseFloat(s, t.Bits())
+	default:
+		return s
+	}
+	if err != nil {
+		return s
+	}
+
+	return v
+}
+
+// maskViolation masks the value of a secret in the violation at location, see validateConfig.
+func maskViolation(line string, secrets map[string]string) string {
+	for ptr, v := range secrets {
+		prefix := "- at '" + ptr + "': "
+		i := strings.Index(line, prefix)
+		if i < 0 || strings.TrimSpace(line[:i]) != "" {
+			continue
+		}
+
+		prefix = line[:i+len(prefix)]
+		msg := strings.ReplaceAll(line[len(prefix):], v, redacted)
+		return prefix + schemaNumber.ReplaceAllString(msg, "got "+redacted+", want")
+	}
+
+	return line
+}
+
+func validateSchema(schema []byte, data any, secrets map[string]string) error {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(schema))
+	if err != nil {
+		return fmt.Errorf("parse schema: %w", err)
+	}
+
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(schemaResource, doc); err != nil {
+		return fmt.Errorf("add schema: %w", err)
+	}
+
+	sch, err := compiler.Compile(schemaResource)
+	if err != nil {
+		return fmt.Errorf("compile schema: %w", err)
+	}
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("marshal config: %w", err)
+	}
+
+	v, err := jsonschema.UnmarshalJSON(bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("unmarshal config: %w", err)
+	}
+
+	if err := sch.Validate(v); err != nil {
+		// drop the header naming the schema location, keep one line per violation
+		_, violations, _ := strings.Cut(err.Error(), "\n")
+		lines := strings.Split(violations, "\n")
+		for i, line := range lines {
+			lines[i] = maskViolation(line, secrets)
+		}
+		violations = strings.Join(lines, "\n")
+
+		return fmt.Errorf("%w:\n%s", ErrSchemaViolation, violations)
+	}
+
+	return nil
+}
@@ -0,0 +1,213 @@
+package config_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type schemaConfig struct {
+	Server struct {
+		Listen  string          `koanf:"listen"  desc:"Address to listen on"`
+		Timeout config.Duration `koanf:"timeout"`
+		Mode    string          `koanf:"mode"    oneof:"http,https"`
+		Port    uint16          `koanf:"port"`
+	} `koanf:"server"`
+	Password config.Secret        `koanf:"password"`
+	Format   logFormat            `koanf:"format"`
+	Tags     []string             `koanf:"tags"`
+	Labels   map[string]int       `koanf:"labels"`
+	Started  time.Time            `koanf:"started"`
+	Retries  config.Optional[int] `koanf:"retries"`
+	OldName  string               `koanf:"old_name" deprecated:"use name"`
+	Ignored  string               `koanf:"-"`
+	Extra    map[string]any       `koanf:"extra"`
+}
+
+// TestGenerateSchema tests JSON Schema generation from a configuration struct
+func TestGenerateSchema(t *testing.T) {
+	b, err := config.GenerateSchema[schemaConfig]()
+	require.NoError(t, err)
+
+	var s struct {
+		Schema     string                     `json:"$schema"`
+		Title      string                     `json:"title"`
+		Type       string                     `json:"type"`
+		Properties map[string]json.RawMessage `json:"properties"`
+	}
+	require.NoError(t, json.Unmarshal(b, &s))
+
+	assert.Equal(t, "https://json-schema.org/draft/2020-12/schema", s.Schema)
+	assert.Equal(t, "schemaConfig", s.Title)
+	assert.Equal(t, "object", s.Type)
+	assert.NotContains(t, s.Properties, "ignored")
+
+	assert.JSONEq(t, `{"type":"object","properties":{
+		"listen":{"type":"string","description":"Address to listen on"},
+		"timeout":{"type":"string"},
+		"mode":{"type":"string","enum":["http","https"]},
+		"port":{"type":"integer","minimum":0}
+	}}`, string(s.Properties["server"]))
+	assert.JSONEq(t, `{"type":"string","writeOnly":true}`, string(s.Properties["password"]))
+	assert.JSONEq(t, `{"type":"string","enum":["json","text"]}`, string(s.Properties["format"]))
+	assert.JSONEq(t, `{"type":"array","items":{"type":"string"}}`, string(s.Properties["tags"]))
+	assert.JSONEq(t, `{"type":"object","additionalProperties":{"type":"integer"}}`, string(s.Properties["labels"]))
+	assert.JSONEq(t, `{"type":"string","format":"date-time"}`, string(s.Properties["started"]))
+	assert.JSONEq(t, `{"type":"integer"}`, string(s.Properties["retries"]))
+	assert.JSONEq(t, `{"type":"string","deprecated":true}`, string(s.Properties["old_name"]))
+	assert.JSONEq(t, `{"type":"object","additionalProperties":{}}`, string(s.Properties["extra"]))
+}
+
+// TestGenerateSchemaUnsupportedTarget tests that non-struct types are rejected
+func TestGenerateSchemaUnsupportedTarget(t *testing.T) {
+	_, err := config.GenerateSchema[string]()
+	require.ErrorIs(t, err, config.ErrUnsupportedTarget)
+}
+
+type validatedConfig struct {
+	Server struct {
+		Host string `koanf:"host"`
+		Port int    `koanf:"port"`
+	} `koanf:"server"`
+	Replicas config.Optional[int] `koanf:"replicas"`
+}
+
+const validatedSchema = `{
+	"type": "object",
+	"properties": {
+		"server": {
+			"type": "object",
+			"properties": {
+				"host": {"type": "string", "minLength": 1},
+				"port": {"type": "integer", "minimum": 1, "maximum": 65535}
+			}
+		},
+		"replicas": {"type": "integer"}
+	}
+}`
+
+// TestWithSchema tests that the loaded configuration is validated against a JSON Schema
+func TestWithSchema(t *testing.T) {
+	t.Setenv("SERVER__PORT", "8080")
+
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	yamlFile := writeTempFile(t, tmpDir, "config.yaml", "server:\n  host: localhost\n")
+
+	var cfg validatedConfig
+	require.NoError(t, config.Load(&cfg, config.WithLocalYAML(yamlFile), config.WithSchema([]byte(validatedSchema))))
+	assert.Equal(t, 8080, cfg.Server.Port)
+
+	t.Setenv("SERVER__PORT", "70000")
+	err := config.Load(&cfg, config.WithLocalYAML(yamlFile), config.WithSchema([]byte(validatedSchema)))
+	require.ErrorIs(t, err, config.ErrSchemaViolation)
+	assert.Contains(t, err.Error(), "/server/port")
+}
+
+// TestWithSchemaGenerated tests validation against a generated schema
+func TestWithSchemaGenerated(t *testing.T) {
+	schema, err := config.GenerateSchema[schemaConfig]()
+	require.NoError(t, err)
+
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	yamlFile := writeTempFile(t, tmpDir, "config.yaml", "server:\n  timeout: 5s\nformat: json\n")
+
+	var cfg schemaConfig
+	require.NoError(t, config.Load(&cfg, config.WithLocalYAML(yamlFile), config.WithSchema(schema)))
+}
+
+// TestWithSchemaSecrets tests that secrets are validated with their types and masked in violations
+func TestWithSchemaSecrets(t *testing.T) {
+	type secretConfig struct {
+		PIN      int           `koanf:"pin"      secret:"true"`
+		Password config.Secret `koanf:"password"`
+	}
+
+	schema, err := config.GenerateSchema[secretConfig]()
+	require.NoError(t, err)
+
+	var cfg secretConfig
+	require.NoError(t, config.Load(&cfg,
+		config.WithEnviron(map[string]string{"PIN": "1234", "PASSWORD": "hunter2"}),
+		config.WithSchema(schema),
+	))
+	assert.Equal(t, 1234, cfg.PIN)
+
+	const strict = `{"type": "object", "properties": {
+		"pin": {"type": "integer", "maximum": 999},
+		"password": {"type": "string", "pattern": "^[0-9]+$"}
+	}}`
+	err = config.Load(&cfg,
+		config.WithEnviron(map[string]string{"PIN": "1234", "PASSWORD": "hunter2"}),
+		config.WithSchema([]byte(strict)),
+	)
+	require.ErrorIs(t, err, config.ErrSchemaViolation)
+	assert.Contains(t, err.Error(), "/pin")
+	assert.Contains(t, err.Error(), "/password")
+	assert.NotContains(t, err.Error(), "1,234")
+	assert.NotContains(t, err.Error(), "hunter2")
+}
+
+// TestWithSchemaUnknownKeys tests that keys not mapped to a field are validated
+func TestWithSchemaUnknownKeys(t *testing.T) {
+	const strict = `{"type": "object", "additionalProperties": false, "properties": {
+		"port": {"type": "integer"}
+	}}`
+
+	type portConfig struct {
+		Port int `koanf:"port"`
+	}
+
+	var cfg portConfig
+	err := config.Load(&cfg,
+		config.WithEnviron(map[string]string{"PROT": "80"}),
+		config.WithSchema([]byte(strict)),
+	)
+	require.ErrorIs(t, err, config.ErrSchemaViolation)
+	assert.Contains(t, err.Error(), "prot")
+}
+
+// TestWithSchemaUnset tests that unset fields are not validated with their zero values
+func TestWithSchemaUnset(t *testing.T) {
+	const strict = `{"type": "object", "properties": {
+		"port": {"type": "integer", "minimum": 1}
+	}}`
+
+	type portConfig struct {
+		Host string `koanf:"host"`
+		Port int    `koanf:"port"`
+	}
+
+	var cfg portConfig
+	require.NoError(t, config.Load(&cfg,
+		config.WithEnviron(map[string]string{"HOST": "localhost"}),
+		config.WithSchema([]byte(strict)),
+	))
+	assert.Zero(t, cfg.Port)
+
+	err := config.Load(&cfg,
+		config.WithEnviron(map[string]string{"PORT": "0"}),
+		config.WithSchema([]byte(strict)),
+	)
+	require.ErrorIs(t, err, config.ErrSchemaViolation)
+	assert.Contains(t, err.Error(), "/port")
+}
+
+// TestValidateFile tests validating a single YAML file against a JSON Schema
+func TestValidateFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	valid := writeTempFile(t, tmpDir, "valid.yaml", "server:\n  host: localhost\n  port: 8080\n")
+	invalid := writeTempFile(t, tmpDir, "invalid.yaml", "server:\n  host: ''\n  port: http\n")
+
+	require.NoError(t, config.ValidateFile(valid, []byte(validatedSchema)))
+
+	err := config.ValidateFile(invalid, []byte(validatedSchema))
+	require.ErrorIs(t, err, config.ErrSchemaViolation)
+	assert.Contains(t, err.Error(), "/server/host")
+	assert.Contains(t, err.Error(), "/server/port")
+}
@@ -0,0 +1,37 @@
+package config
+
+// Secret is a string configuration value that must not be exposed.
+//
+// Secret values are masked when formatted with fmt, marshaled as text, JSON or YAML,
+// and when the configuration is rendered by Dump. Use Value to get the actual value.
+type Secret string
+
+const redacted = "******"
+
+// Value returns the actual secret value.
+func (s Secret) Value() string {
+	return string(s)
+}
+
+// String returns a masked representation of the secret.
+func (s Secret) String() string {
+	return mask(string(s))
+}
+
+// GoString returns a masked representation of the secret for the %#v verb.
+func (s Secret) GoString() string {
+	return s.String()
+}
+
+// MarshalText returns a masked representation of the secret.
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func mask(s string) string {
+	if s == "" {
+		return ""
+	}
+
+	return redacted
+}
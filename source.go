@@ -0,0 +1,220 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/knadh/koanf/v2"
+)
+
+const cacheDirMode = 0o700
+
+// Source is a custom configuration source, such as a remote configuration server.
+//
+// Custom sources are merged after the local YAML file and before `.env` and environment
+// variables, in the order they were added with WithSource.
+type Source interface {
+	// Name identifies the source in provenance, hooks and errors.
+	Name() string
+	// Load returns the configuration of the source as a nested map.
+	Load(ctx context.Context) (map[string]any, error)
+}
+
+// Backoff returns the delay before the given retry attempt, starting at 1,
+// or false to give up.
+type Backoff func(attempt int) (time.Duration, bool)
+
+// ConstantBackoff retries up to the given number of times with a fixed delay.
+func ConstantBackoff(delay time.Duration, retries int) Backoff {
+	return func(attempt int) (time.Duration, bool) {
+		return delay, attempt <= retries
+	}
+}
+
+// ExponentialBackoff retries up to the given number of times, doubling the delay
+// from initial after each attempt, up to maxDelay.
+func ExponentialBackoff(initial, maxDelay time.Duration, retries int) Backoff {
+	return func(attempt int) (time.Duration, bool) {
+		delay := initial
+		for i := 1; i < attempt && delay < maxDelay; i++ {
+			delay *= 2
+		}
+
+		return min(delay, maxDelay), attempt <= retries
+	}
+}
+
+// sourceLayer returns the layer loading a custom source with the timeout and retry policy of options.
+func sourceLayer(ctx context.Context, src Source, options *options) layer {
+	return layer{
+		name:     src.Name(),
+		location: "",
+		foldCase: false,
+		vars:     nil,
+		load: func(k *koanf.Koanf) (int, error) {
+			m, size, err := loadCachedSource(ctx, src, options)
+			if err != nil {
+				return 0, fmt.Errorf("load %s: %w", src.Name(), err)
+			}
+
+			if err := k.Load(rawProvider(m), nil); err != nil {
+				return 0, fmt.Errorf("load %s: %w", src.Name(), err)
+			}
+
+			return size, nil
+		},
+	}
+}
+
+// loadCachedSource loads the source, keeping a copy in the cache directory, if any,
+// to fall back to when the source is unavailable. It also returns the size of the raw document
+// of a RawSource, 0 for other sources and cached copies.
+func loadCachedSource(ctx context.Context, src Source, options *options) (map[string]any, int, error) {
+	m, size, err := loadSource(ctx, src, options)
+	if options.cacheDir == "" {
+		return m, size, err
+	}
+
+	if err == nil {
+		if options.dryRun {
+			return m, size, nil
+		}
+		if err := writeCache(options.cacheDir, src.Name(), m); err != nil {
+			options.logger.Warn("config source cache not updated", slog.String("source", src.Name()), slog.String("error", err.Error()))
+		}
+		return m, size, nil
+	}
+
+	cached, age, cacheErr := readCache(options.cacheDir, src.Name())
+	if cacheErr != nil {
+		return nil, 0, err
+	}
+
+	options.logger.Warn("config source unavailable, using cached copy",
+		slog.String("source", src.Name()),
+		slog.Duration("age", age),
+		slog.String("error", err.Error()),
+	)
+
+	return cached, 0, nil
+}
+
+func loadSource(ctx context.Context, src Source, options *options) (map[string]any, int, error) {
+	b := options.breaker
+	if b == nil {
+		return retrySource(ctx, src, options)
+	}
+
+	if !b.allow(src.Name()) {
+		return nil, 0, ErrCircuitOpen
+	}
+
+	m, size, err := retrySource(ctx, src, options)
+	if !options.dryRun {
+		b.record(src.Name(), err)
+	}
+
+	return m, size, err
+}
+
+func retrySource(ctx context.Context, src Source, options *options) (map[string]any, int, error) {
+	for attempt := 1; ; attempt++ {
+		m, size, err := loadOnce(ctx, src, options.sourceTimeout, options.verifiers[src.Name()])
+		if err == nil {
+			return m, size, nil
+		}
+
+		delay, retry := time.Duration(0), false
+		if options.backoff != nil {
+			delay, retry = options.backoff(attempt)
+		}
+		if !retry {
+			return nil, 0, err
+		}
+
+		options.logger.Warn("config source failed, retrying",
+			slog.String("source", src.Name()),
+			slog.Int("attempt", attempt),
+			slog.Duration("delay", delay),
+			slog.String("error", err.Error()),
+		)
+
+		select {
+		case <-ctx.Done():
+			return nil, 0, fmt.Errorf("%w (last error: %w)", ctx.Err(), err)
+		case <-time.After(delay):
+		}
+	}
+}
+
+// loadOnce loads the source, through its raw document if it is a RawSource, so its size is known.
+func loadOnce(ctx context.Context, src Source, timeout time.Duration, verify Verifier) (map[string]any, int, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	if rs, ok := src.(RawSource); ok {
+		return loadRaw(ctx, rs, verify)
+	}
+	if verify != nil {
+		return nil, 0, fmt.Errorf("%w: source does not provide raw documents", ErrVerification)
+	}
+
+	m, err := src.Load(ctx)
+	return m, 0, err //nolint:wrapcheck // wrapped by the caller
+}
+
+// cachePath returns the path of the cache file of the named source in dir.
+func cachePath(dir, name string) string {
+	safe := strings.Map(func(r rune) rune {
+		if r == '-' || r == '_' || r == '.' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, name)
+
+	return filepath.Join(dir, safe+".json")
+}
+
+func writeCache(dir, name string, m map[string]any) error {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+
+	if err := os.MkdirAll(dir, cacheDirMode); err != nil {
+		return fmt.Errorf("create cache dir: %w", err)
+	}
+
+	return writeFile(cachePath(dir, name), b)
+}
+
+// readCache returns the cached configuration of the named source and its age.
+func readCache(dir, name string) (map[string]any, time.Duration, error) {
+	path := cachePath(dir, name)
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("read cache: %w", err)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("read cache: %w", err)
+	}
+
+	var m map[string]any
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, 0, fmt.Errorf("parse cache %s: %w", path, err)
+	}
+
+	return m, time.Since(info.ModTime()), nil
+}
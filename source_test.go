@@ -0,0 +1,149 @@
+package config_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errUnavailable = errors.New("unavailable")
+
+// fakeSource fails the first `failures` loads, then returns data.
+type fakeSource struct {
+	data     map[string]any
+	failures int
+	attempts int
+	block    bool
+}
+
+func (s *fakeSource) Name() string { return "remote" }
+
+func (s *fakeSource) Load(ctx context.Context) (map[string]any, error) {
+	s.attempts++
+	if s.block {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	if s.attempts <= s.failures {
+		return nil, errUnavailable
+	}
+	return s.data, nil
+}
+
+// TestWithSource tests that custom sources are merged between the YAML file and env
+func TestWithSource(t *testing.T) {
+	t.Setenv("SERVER__PORT", "9090")
+
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	yamlFile := writeTempFile(t, tmpDir, "config.yaml", "database:\n  host: file-host\n  port: 5432\n")
+
+	src := &fakeSource{data: map[string]any{
+		"database": map[string]any{"host": "remote-host"},
+		"server":   map[string]any{"port": 8080},
+	}}
+
+	var p config.Provenance
+	var cfg TestConfig
+	require.NoError(t, config.Load(&cfg, config.WithLocalYAML(yamlFile), config.WithSource(src), config.WithProvenance(&p)))
+
+	assert.Equal(t, "remote-host", cfg.Database.Host)
+	assert.Equal(t, 5432, cfg.Database.Port)
+	assert.Equal(t, 9090, cfg.Server.Port)
+	assert.Equal(t, "remote", p["database.host"].Source)
+}
+
+// TestWithRetry tests that failed custom sources are retried according to the backoff
+func TestWithRetry(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	src := &fakeSource{data: map[string]any{"server": map[string]any{"port": 8080}}, failures: 2}
+
+	var cfg TestConfig
+	err := config.Load(&cfg, config.WithSource(src), config.WithRetry(config.ConstantBackoff(time.Millisecond, 3)))
+	require.NoError(t, err)
+	assert.Equal(t, 8080, cfg.Server.Port)
+	assert.Equal(t, 3, src.attempts)
+
+	src = &fakeSource{failures: 10}
+	err = config.Load(&cfg, config.WithSource(src), config.WithRetry(config.ConstantBackoff(time.Millisecond, 2)))
+	require.ErrorIs(t, err, errUnavailable)
+	assert.Contains(t, err.Error(), "load remote")
+	assert.Equal(t, 3, src.attempts)
+}
+
+// TestWithSourceTimeout tests that a hanging source is bounded by the timeout
+func TestWithSourceTimeout(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	src := &fakeSource{block: true}
+
+	var cfg TestConfig
+	err := config.Load(&cfg,
+		config.WithSource(src),
+		config.WithSourceTimeout(10*time.Millisecond),
+		config.WithRetry(config.ConstantBackoff(time.Millisecond, 1)),
+	)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 2, src.attempts)
+}
+
+// TestExponentialBackoff tests the delays and the retry limit
+func TestExponentialBackoff(t *testing.T) {
+	b := config.ExponentialBackoff(100*time.Millisecond, time.Second, 5)
+
+	var delays []time.Duration
+	for attempt := 1; ; attempt++ {
+		d, ok := b(attempt)
+		if !ok {
+			break
+		}
+		delays = append(delays, d)
+	}
+
+	assert.Equal(t, []time.Duration{
+		100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second,
+	}, delays)
+}
+
+// TestWithSourceCache tests falling back to the cached copy of an unavailable source
+func TestWithSourceCache(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	cacheDir := filepath.Join(tmpDir, "cache")
+
+	src := &fakeSource{data: map[string]any{"database": map[string]any{"host": "remote-host", "port": 5432}}}
+
+	var cfg TestConfig
+	require.NoError(t, config.Load(&cfg, config.WithSource(src), config.WithSourceCache(cacheDir)))
+
+	info, err := os.Stat(filepath.Join(cacheDir, "remote.json"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	var buf bytes.Buffer
+	src = &fakeSource{failures: 1}
+	cfg = TestConfig{}
+	err = config.Load(&cfg,
+		config.WithSource(src),
+		config.WithSourceCache(cacheDir),
+		config.WithLogger(slog.New(slog.NewTextHandler(&buf, nil))),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "remote-host", cfg.Database.Host)
+	assert.Equal(t, 5432, cfg.Database.Port)
+	assert.Contains(t, buf.String(), `msg="config source unavailable, using cached copy" source=remote`)
+
+	src = &fakeSource{failures: 1}
+	err = config.Load(&cfg, config.WithSource(src), config.WithSourceCache(filepath.Join(tmpDir, "empty")))
+	require.ErrorIs(t, err, errUnavailable)
+}
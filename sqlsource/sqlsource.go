@@ -0,0 +1,122 @@
+// Package sqlsource provides configuration sources reading settings from a database table.
+//
+//	src := sqlsource.KeyValue(db, "settings", "SELECT key, value FROM settings WHERE app = $1", "api")
+//	config.Load(&cfg, config.WithSource(src))
+package sqlsource
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/go-core-fx/config"
+	kmaps "github.com/knadh/koanf/maps"
+)
+
+// Source is a config.Source running a query.
+type Source struct {
+	name  string
+	db    *sql.DB
+	query string
+	args  []any
+	scan  func(rows *sql.Rows, m map[string]any) error
+}
+
+var _ config.Source = (*Source)(nil)
+
+// KeyValue returns a source named name running a query returning key and value columns, e.g.
+// `database.pool.size | 10`. Keys are separated by dots, values are strings, and JSON objects
+// and arrays are parsed like environment variables. Rows with a NULL value are skipped.
+func KeyValue(db *sql.DB, name, query string, args ...any) *Source {
+	return &Source{name: name, db: db, query: query, args: args, scan: scanKeyValue}
+}
+
+// JSON returns a source named name running a query returning a single column of JSON objects,
+// merged in the order of the rows.
+func JSON(db *sql.DB, name, query string, args ...any) *Source {
+	return &Source{name: name, db: db, query: query, args: args, scan: scanJSON}
+}
+
+// Name returns the name of the source.
+func (s *Source) Name() string {
+	return s.name
+}
+
+// Load runs the query.
+func (s *Source) Load(ctx context.Context) (map[string]any, error) {
+	rows, err := s.db.QueryContext(ctx, s.query, s.args...)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+	defer rows.Close()
+
+	m := map[string]any{}
+	for rows.Next() {
+		if err := s.scan(rows, m); err != nil {
+			return nil, err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return m, nil
+}
+
+func scanKeyValue(rows *sql.Rows, m map[string]any) error {
+	var key string
+	var value sql.NullString
+	if err := rows.Scan(&key, &value); err != nil {
+		return fmt.Errorf("scan: %w", err)
+	}
+	if !value.Valid {
+		return nil
+	}
+
+	path := strings.Split(key, ".")
+	node := m
+	for _, segment := range path[:len(path)-1] {
+		child, ok := node[segment].(map[string]any)
+		if !ok {
+			child = map[string]any{}
+			node[segment] = child
+		}
+		node = child
+	}
+	node[path[len(path)-1]] = parseValue(value.String)
+
+	return nil
+}
+
+// parseValue parses JSON objects and arrays, other values are kept as strings.
+func parseValue(s string) any {
+	trimmed := strings.TrimSpace(s)
+	if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		var v any
+		if err := json.Unmarshal([]byte(trimmed), &v); err == nil {
+			return v
+		}
+	}
+
+	return s
+}
+
+func scanJSON(rows *sql.Rows, m map[string]any) error {
+	var data sql.RawBytes
+	if err := rows.Scan(&data); err != nil {
+		return fmt.Errorf("scan: %w", err)
+	}
+	if data == nil {
+		return nil
+	}
+
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("parse: %w", err)
+	}
+	kmaps.Merge(doc, m)
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package sqlsource_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/go-core-fx/config"
+	"github.com/go-core-fx/config/sqlsource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errNotSupported = errors.New("not supported")
+
+// fakeDriver returns the rows registered for a query.
+type fakeDriver map[string][][]driver.Value
+
+func (d fakeDriver) Open(string) (driver.Conn, error) { return fakeConn(d), nil }
+
+type fakeConn fakeDriver
+
+func (c fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errNotSupported }
+func (c fakeConn) Close() error                        { return nil }
+func (c fakeConn) Begin() (driver.Tx, error)           { return nil, errNotSupported }
+
+func (c fakeConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	rows, ok := c[query]
+	if !ok {
+		return nil, errNotSupported
+	}
+	return &fakeRows{rows: rows}, nil
+}
+
+type fakeRows struct {
+	rows [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string {
+	if len(r.rows) == 0 {
+		return nil
+	}
+	return make([]string, len(r.rows[0]))
+}
+
+func (r *fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+type testConfig struct {
+	Database struct {
+		Host string `koanf:"host"`
+		Port int    `koanf:"port"`
+	} `koanf:"database"`
+	Hosts []string `koanf:"hosts"`
+}
+
+func open(t *testing.T, rows fakeDriver) *sql.DB {
+	t.Helper()
+	sql.Register(t.Name(), rows)
+	db, err := sql.Open(t.Name(), "")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+// TestKeyValue tests loading key/value rows into nested keys
+func TestKeyValue(t *testing.T) {
+	t.Chdir(t.TempDir())
+	db := open(t, fakeDriver{"SELECT key, value FROM settings": {
+		{"database.host", "db"},
+		{"database.port", "5432"},
+		{"hosts", `["a", "b"]`},
+		{"unset", nil},
+	}})
+
+	var c testConfig
+	src := sqlsource.KeyValue(db, "settings", "SELECT key, value FROM settings")
+	require.NoError(t, config.Load(&c, config.WithSource(src), config.WithEnviron(nil)))
+	assert.Equal(t, "db", c.Database.Host)
+	assert.Equal(t, 5432, c.Database.Port)
+	assert.Equal(t, []string{"a", "b"}, c.Hosts)
+
+	src = sqlsource.KeyValue(db, "settings", "SELECT * FROM missing")
+	require.ErrorIs(t, config.Load(&c, config.WithSource(src), config.WithEnviron(nil)), errNotSupported)
+}
+
+// TestJSON tests merging JSON documents in row order
+func TestJSON(t *testing.T) {
+	t.Chdir(t.TempDir())
+	db := open(t, fakeDriver{"SELECT settings FROM apps": {
+		{`{"database": {"host": "base", "port": 5432}}`},
+		{[]byte(`{"database": {"host": "override"}}`)},
+	}})
+
+	var c testConfig
+	src := sqlsource.JSON(db, "apps", "SELECT settings FROM apps")
+	require.NoError(t, config.Load(&c, config.WithSource(src), config.WithEnviron(nil)))
+	assert.Equal(t, "override", c.Database.Host)
+	assert.Equal(t, 5432, c.Database.Port)
+}
@@ -0,0 +1,82 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/go-core-fx/config"
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/knadh/koanf/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type HTTPServerOptions struct {
+	Listen string `koanf:"listen"`
+	Mode   string `koanf:"mode"   oneof:"http,https"`
+}
+
+type serviceConfig struct {
+	API struct {
+		HTTPServerOptions
+
+		Prefix string `koanf:"prefix"`
+	} `koanf:"api"`
+	Admin struct {
+		HTTPServerOptions `koanf:",squash"`
+	} `koanf:"admin"`
+}
+
+// TestEmbeddedStructSquashDecoderConfig tests that embedded structs are squashed with a custom decoder config
+func TestEmbeddedStructSquashDecoderConfig(t *testing.T) {
+	var cfg serviceConfig
+	err := config.Load(&cfg,
+		config.WithEnviron(map[string]string{"API__LISTEN": ":8080", "ADMIN__MODE": "http"}),
+		config.WithUnmarshalConf(koanf.UnmarshalConf{
+			Tag:           "",
+			FlatPaths:     false,
+			DecoderConfig: &mapstructure.DecoderConfig{ErrorUnused: true, WeaklyTypedInput: true},
+		}),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, ":8080", cfg.API.Listen)
+	assert.Equal(t, "http", cfg.Admin.Mode)
+}
+
+// TestEmbeddedStructSquash tests that embedded structs unmarshal from flat keys
+func TestEmbeddedStructSquash(t *testing.T) {
+	t.Setenv("ADMIN__LISTEN", ":9090")
+
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	yamlFile := writeTempFile(t, tmpDir, "config.yaml", `api:
+  listen: :8080
+  mode: https
+  prefix: /v1
+admin:
+  mode: http`)
+
+	var cfg serviceConfig
+	require.NoError(t, config.Load(&cfg, config.WithLocalYAML(yamlFile)))
+
+	assert.Equal(t, ":8080", cfg.API.Listen)
+	assert.Equal(t, "https", cfg.API.Mode)
+	assert.Equal(t, "/v1", cfg.API.Prefix)
+	assert.Equal(t, ":9090", cfg.Admin.Listen)
+	assert.Equal(t, "http", cfg.Admin.Mode)
+
+	out, err := config.Dump(cfg, config.FormatYAML)
+	require.NoError(t, err)
+	assert.Contains(t, string(out), "api:\n    listen: :8080\n    mode: https\n    prefix: /v1\n")
+}
+
+// TestEmbeddedStructSquashEnum tests that enum errors of embedded fields use flat keys
+func TestEmbeddedStructSquashEnum(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	yamlFile := writeTempFile(t, tmpDir, "config.yaml", "api:\n  mode: ftp\n")
+
+	var cfg serviceConfig
+	err := config.Load(&cfg, config.WithLocalYAML(yamlFile))
+	require.ErrorIs(t, err, config.ErrInvalidEnum)
+	assert.Contains(t, err.Error(), "api.mode")
+}
@@ -0,0 +1,125 @@
+package config_test
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type apiConfig struct {
+	ListenAddr string `json:"listen_addr"`
+	Limits     struct {
+		MaxBodySize int    `yaml:"max_body_size"`
+		Mode        string `json:"mode"        oneof:"strict,lenient"`
+	} `json:"limits"`
+	Name string `json:"display_name" koanf:"name"`
+}
+
+// TestWithTag tests decoding with an alternative struct tag
+func TestWithTag(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	yamlFile := writeTempFile(t, tmpDir, "config.yaml", "listen_addr: :8080\nlimits:\n  mode: strict\n")
+
+	var cfg apiConfig
+	require.NoError(t, config.Load(&cfg, config.WithLocalYAML(yamlFile), config.WithTag("json")))
+	assert.Equal(t, ":8080", cfg.ListenAddr)
+	assert.Equal(t, "strict", cfg.Limits.Mode)
+}
+
+// TestWithTagFallback tests that fields without the primary tag fall back to other tags
+func TestWithTagFallback(t *testing.T) {
+	t.Setenv("LIMITS__MAX_BODY_SIZE", "1024")
+
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	yamlFile := writeTempFile(t, tmpDir, "config.yaml", "listen_addr: :8080\nname: api\nlimits:\n  mode: strict\n")
+
+	var cfg apiConfig
+	err := config.Load(&cfg, config.WithLocalYAML(yamlFile), config.WithTag("koanf", "json", "yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, ":8080", cfg.ListenAddr)
+	assert.Equal(t, "api", cfg.Name)
+	assert.Equal(t, "strict", cfg.Limits.Mode)
+	assert.Equal(t, 1024, cfg.Limits.MaxBodySize)
+}
+
+// TestWithTagFallbackEnum tests that enum errors report fallback tag keys
+func TestWithTagFallbackEnum(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	yamlFile := writeTempFile(t, tmpDir, "config.yaml", "limits:\n  mode: loose\n")
+
+	var cfg apiConfig
+	err := config.Load(&cfg, config.WithLocalYAML(yamlFile), config.WithTag("koanf", "json"))
+	require.ErrorIs(t, err, config.ErrInvalidEnum)
+	assert.Contains(t, err.Error(), "limits.mode")
+}
+
+type describedConfig struct {
+	Database struct {
+		Host string `koanf:"host" desc:"database server hostname"`
+	} `koanf:"database"`
+}
+
+// TestDescTag tests that every generator uses the `desc` tag
+func TestDescTag(t *testing.T) {
+	const desc = "database server hostname"
+
+	example, err := config.GenerateExample[describedConfig](config.FormatYAML)
+	require.NoError(t, err)
+	assert.Contains(t, string(example), "# "+desc)
+
+	env, err := config.GenerateEnvExample[describedConfig]()
+	require.NoError(t, err)
+	assert.Contains(t, string(env), "# "+desc+"\nDATABASE__HOST=")
+
+	schema, err := config.GenerateSchema[describedConfig]()
+	require.NoError(t, err)
+	assert.Contains(t, string(schema), `"description": "`+desc+`"`)
+
+	markdown, err := config.GenerateMarkdown[describedConfig]()
+	require.NoError(t, err)
+	assert.Contains(t, string(markdown), "| "+desc+" |")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	config.RegisterFlags[describedConfig](fs)
+	assert.Equal(t, desc, fs.Lookup("database.host").Usage)
+}
+
+// TestTags tests rendering structs loaded with WithTag under the same keys
+func TestTags(t *testing.T) {
+	type poolConfig struct {
+		MaxConns int `json:"max_conns"`
+	}
+
+	c := poolConfig{MaxConns: 7}
+	tags := config.Tags("json")
+
+	b, err := config.Dump(&c, config.FormatYAML, tags)
+	require.NoError(t, err)
+	assert.Equal(t, "max_conns: 7\n", string(b))
+
+	vars, err := config.Env(&c, tags)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"MAX_CONNS=7"}, vars)
+
+	b, err = config.GenerateEnvExample[poolConfig](tags)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), "MAX_CONNS=0\n")
+
+	schema, err := config.GenerateSchema[poolConfig](tags)
+	require.NoError(t, err)
+	assert.Contains(t, string(schema), `"max_conns"`)
+
+	var loaded poolConfig
+	require.NoError(t, config.Load(&loaded,
+		config.WithTag("json"),
+		config.WithEnviron(map[string]string{"MAX_CONNS": "7"}),
+		config.WithSchema([]byte(`{"type": "object", "required": ["max_conns"]}`)),
+	))
+	assert.Equal(t, c, loaded)
+}
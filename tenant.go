@@ -0,0 +1,57 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/knadh/koanf/v2"
+)
+
+const tenantsKey = "tenants"
+
+var ErrUnknownTenant = errors.New("unknown tenant")
+
+// WithTenant loads the configuration of the given tenant: the section `tenants.<id>` is merged
+// over the base configuration, after all sources, and the `tenants` section is dropped.
+// If the section does not exist, Load returns ErrUnknownTenant.
+// Keys locked with WithLockedKeys are not overridden by the overlay.
+func WithTenant(id string) Option {
+	return func(o *options) {
+		o.tenant = id
+	}
+}
+
+// ForTenant loads the configuration of the given tenant with the watcher's options and runtime overrides,
+// see WithTenant. The current configuration of the watcher is not changed, and neither OnLoad hooks
+// nor WithProvenance of the watcher are applied.
+func (w *Watcher[T]) ForTenant(ctx context.Context, id string) (T, error) {
+	w.reloadMu.Lock()
+	opts := append(w.options(), WithTenant(id), withDryRun())
+	w.reloadMu.Unlock()
+
+	var c T
+	if err := LoadContext(ctx, &c, opts...); err != nil {
+		return c, err
+	}
+
+	return c, nil
+}
+
+// applyTenant merges the overlay of the tenant over the base configuration in k.
+func applyTenant(k *koanf.Koanf, id string, options *options) error {
+	key := tenantsKey + options.delim + id
+	if !k.Exists(key) {
+		return fmt.Errorf("%w: %s", ErrUnknownTenant, id)
+	}
+
+	overlay := k.Cut(key)
+	k.Delete(tenantsKey)
+	options.dropLocked(k, overlay, "tenant "+id)
+
+	if err := mergeLayer(k, overlay, options); err != nil {
+		return fmt.Errorf("tenant %s: %w", id, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package config_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const tenantYAML = `
+database:
+  host: shared
+  port: 5432
+feature_flags:
+  new_checkout: false
+tenants:
+  acme:
+    database:
+      host: acme-db
+    feature_flags:
+      new_checkout: true
+`
+
+// TestWithTenant tests merging a tenant overlay over the base configuration
+func TestWithTenant(t *testing.T) {
+	files := fstest.MapFS{"config.yaml": {Data: []byte(tenantYAML)}}
+
+	var c TestConfig
+	require.NoError(t, loadYAML(t, &c, files, config.WithTenant("acme"), config.WithEnviron(map[string]string{"SERVER__PORT": "80"})))
+	assert.Equal(t, "acme-db", c.Database.Host)
+	assert.Equal(t, 5432, c.Database.Port)
+	assert.Equal(t, 80, c.Server.Port)
+	assert.Equal(t, map[string]bool{"new_checkout": true}, c.FeatureFlags)
+
+	require.ErrorIs(t, loadYAML(t, &c, files, config.WithTenant("globex")), config.ErrUnknownTenant)
+}
+
+// TestWithTenantLockedKeys tests that tenant overlays do not override locked keys
+func TestWithTenantLockedKeys(t *testing.T) {
+	files := fstest.MapFS{"config.yaml": {Data: []byte("database:\n  host: locked\n  port: 5432\n")}}
+
+	var c TestConfig
+	require.NoError(t, loadYAML(t, &c, files,
+		config.WithTenant("acme"),
+		config.WithLockedKeys("database.host"),
+		config.WithEnviron(map[string]string{"TENANTS__ACME__DATABASE__HOST": "evil", "TENANTS__ACME__DATABASE__PORT": "6432"}),
+	))
+	assert.Equal(t, "locked", c.Database.Host)
+	assert.Equal(t, 6432, c.Database.Port)
+}
+
+// TestWatcherForTenant tests materializing tenant configurations from a watcher
+func TestWatcherForTenant(t *testing.T) {
+	var p config.Provenance
+	loads := 0
+	w, err := config.NewWatcher[TestConfig](t.Context(),
+		config.WithFS(fstest.MapFS{"config.yaml": {Data: []byte(tenantYAML)}}),
+		config.WithLocalYAML("config.yaml"),
+		config.WithEnviron(nil),
+		config.WithProvenance(&p),
+		config.OnLoad(func(config.LoadInfo) { loads++ }),
+	)
+	require.NoError(t, err)
+	require.NoError(t, w.Set(t.Context(), "database.port", 6432))
+
+	loads = 0
+
+	c, err := w.ForTenant(t.Context(), "acme")
+	require.NoError(t, err)
+	assert.Zero(t, loads)
+	_, ok := p.Lookup("tenants.acme.database.host")
+	assert.True(t, ok)
+	assert.Equal(t, "acme-db", c.Database.Host)
+	assert.Equal(t, 6432, c.Database.Port)
+	assert.Equal(t, "shared", w.Get().Database.Host)
+}
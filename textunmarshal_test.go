@@ -0,0 +1,47 @@
+package config_test
+
+import (
+	"log/slog"
+	"math/big"
+	"net/netip"
+	"testing"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type thirdPartyConfig struct {
+	Bind     netip.Addr     `koanf:"bind"`
+	Gateway  *netip.Addr    `koanf:"gateway"`
+	Allowed  []netip.Prefix `koanf:"allowed"`
+	LogLevel slog.Level     `koanf:"log_level"`
+	Amount   big.Int        `koanf:"amount"`
+	Ratio    big.Float      `koanf:"ratio"`
+}
+
+// TestTextUnmarshalerFields tests that third-party encoding.TextUnmarshaler types decode without wrappers
+func TestTextUnmarshalerFields(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "warn")
+
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	yamlFile := writeTempFile(t, tmpDir, "config.yaml", `bind: 127.0.0.1
+gateway: 10.0.0.1
+allowed:
+  - 10.0.0.0/8
+  - 192.168.0.0/16
+amount: 42
+ratio: 1.5`)
+
+	var cfg thirdPartyConfig
+	require.NoError(t, config.Load(&cfg, config.WithLocalYAML(yamlFile)))
+
+	assert.Equal(t, netip.MustParseAddr("127.0.0.1"), cfg.Bind)
+	require.NotNil(t, cfg.Gateway)
+	assert.Equal(t, netip.MustParseAddr("10.0.0.1"), *cfg.Gateway)
+	assert.Equal(t, []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("192.168.0.0/16")}, cfg.Allowed)
+	assert.Equal(t, slog.LevelWarn, cfg.LogLevel)
+	assert.Equal(t, "42", cfg.Amount.String())
+	assert.Equal(t, "1.5", cfg.Ratio.String())
+}
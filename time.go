@@ -0,0 +1,273 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrInvalidDuration  = errors.New("invalid duration")
+	ErrInvalidTime      = errors.New("invalid time")
+	ErrInvalidLocation  = errors.New("invalid time zone")
+	ErrInvalidDate      = errors.New("invalid date")
+	ErrInvalidTimeOfDay = errors.New("invalid time of day")
+)
+
+// Location is a time zone name such as "America/New_York", resolved when the configuration is loaded.
+type Location struct {
+	loc *time.Location
+}
+
+// Location returns the resolved time zone. The zero value resolves to UTC.
+func (l Location) Location() *time.Location {
+	if l.loc == nil {
+		return time.UTC
+	}
+
+	return l.loc
+}
+
+// IsZero reports whether the value is not set.
+func (l Location) IsZero() bool {
+	return l.loc == nil
+}
+
+// String returns the time zone name.
+func (l Location) String() string {
+	if l.loc == nil {
+		return ""
+	}
+
+	return l.loc.String()
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (l Location) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (l *Location) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*l = Location{}
+		return nil
+	}
+
+	loc, err := time.LoadLocation(string(text))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidLocation, err)
+	}
+
+	l.loc = loc
+	return nil
+}
+
+// Date is a calendar date in the "2006-01-02" form, without a time or time zone.
+type Date struct {
+	year  int
+	month time.Month
+	day   int
+}
+
+// NewDate returns the date for the given year, month and day.
+func NewDate(year int, month time.Month, day int) Date {
+	return Date{year: year, month: month, day: day}
+}
+
+// Year returns the year.
+func (d Date) Year() int {
+	return d.year
+}
+
+// Month returns the month.
+func (d Date) Month() time.Month {
+	return d.month
+}
+
+// Day returns the day of the month.
+func (d Date) Day() int {
+	return d.day
+}
+
+// In returns the start of the date in the given location.
+func (d Date) In(loc *time.Location) time.Time {
+	return time.Date(d.year, d.month, d.day, 0, 0, 0, 0, loc)
+}
+
+// IsZero reports whether the value is not set.
+func (d Date) IsZero() bool {
+	return d == Date{}
+}
+
+// String returns the date in the "2006-01-02" form.
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+
+	return d.In(time.UTC).Format(time.DateOnly)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+//
+// Besides "2006-01-02", RFC 3339 timestamps at midnight are accepted, since YAML
+// parses unquoted dates as timestamps.
+func (d *Date) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*d = Date{}
+		return nil
+	}
+
+	t, err := time.Parse(time.DateOnly, string(text))
+	if err != nil {
+		ts, tsErr := time.Parse(time.RFC3339Nano, string(text))
+		if tsErr != nil || ts.Hour() != 0 || ts.Minute() != 0 || ts.Second() != 0 || ts.Nanosecond() != 0 {
+			return fmt.Errorf("%w: %q: expected YYYY-MM-DD", ErrInvalidDate, text)
+		}
+		t = ts
+	}
+
+	*d = NewDate(t.Year(), t.Month(), t.Day())
+	return nil
+}
+
+// TimeOfDay is a wall-clock time in the "15:04" or "15:04:05" form, without a date or time zone.
+type TimeOfDay struct {
+	hour   int
+	minute int
+	second int
+}
+
+// NewTimeOfDay returns the time of day for the given hour, minute and second.
+func NewTimeOfDay(hour, minute, second int) TimeOfDay {
+	return TimeOfDay{hour: hour, minute: minute, second: second}
+}
+
+// Hour returns the hour.
+func (t TimeOfDay) Hour() int {
+	return t.hour
+}
+
+// Minute returns the minute.
+func (t TimeOfDay) Minute() int {
+	return t.minute
+}
+
+// Second returns the second.
+func (t TimeOfDay) Second() int {
+	return t.second
+}
+
+// SinceMidnight returns the time elapsed since midnight.
+func (t TimeOfDay) SinceMidnight() time.Duration {
+	return time.Duration(t.hour)*time.Hour + time.Duration(t.minute)*time.Minute + time.Duration(t.second)*time.Second
+}
+
+// On returns the time of day on the date of day, in the location of day.
+func (t TimeOfDay) On(day time.Time) time.Time {
+	y, m, d := day.Date()
+	return time.Date(y, m, d, t.hour, t.minute, t.second, 0, day.Location())
+}
+
+// String returns the time of day in the "15:04" form, or "15:04:05" if seconds are set.
+func (t TimeOfDay) String() string {
+	if t.second != 0 {
+		return fmt.Sprintf("%02d:%02d:%02d", t.hour, t.minute, t.second)
+	}
+
+	return fmt.Sprintf("%02d:%02d", t.hour, t.minute)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (t TimeOfDay) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (t *TimeOfDay) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*t = TimeOfDay{}
+		return nil
+	}
+
+	for _, layout := range []string{"15:04", time.TimeOnly} {
+		if parsed, err := time.Parse(layout, string(text)); err == nil {
+			*t = NewTimeOfDay(parsed.Hour(), parsed.Minute(), parsed.Second())
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %q: expected HH:MM or HH:MM:SS", ErrInvalidTimeOfDay, text)
+}
+
+// Duration is a time.Duration that is marshaled and unmarshaled in its string form, e.g. "30s",
+// so configurations containing it can be dumped, saved and diffed without raw nanosecond integers.
+type Duration time.Duration
+
+// Duration returns the value as a time.Duration.
+func (d Duration) Duration() time.Duration {
+	return time.Duration(d)
+}
+
+// String returns the duration in the form "72h3m0.5s".
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Duration) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*d = 0
+		return nil
+	}
+
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidDuration, err)
+	}
+
+	*d = Duration(v)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	b, err := json.Marshal(d.String())
+	if err != nil {
+		return nil, fmt.Errorf("marshal duration: %w", err)
+	}
+	return b, nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Both strings and integer nanoseconds are accepted.
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		return d.UnmarshalText([]byte(s))
+	}
+
+	var n int64
+	if err := json.Unmarshal(b, &n); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidDuration, b)
+	}
+
+	*d = Duration(n)
+	return nil
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (d Duration) MarshalYAML() (any, error) {
+	return d.String(), nil
+}
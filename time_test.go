@@ -0,0 +1,185 @@
+package config_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type timeConfig struct {
+	Timezone config.Location `koanf:"timezone"`
+
+	Maintenance struct {
+		Date  config.Date      `koanf:"date"`
+		Start config.TimeOfDay `koanf:"start"`
+		End   config.TimeOfDay `koanf:"end"`
+	} `koanf:"maintenance"`
+}
+
+// TestLocation tests resolving time zones at load
+func TestLocation(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("TIMEZONE", "America/New_York")
+
+	var cfg timeConfig
+	require.NoError(t, config.Load(&cfg))
+
+	assert.Equal(t, "America/New_York", cfg.Timezone.String())
+	assert.Equal(t, "America/New_York", cfg.Timezone.Location().String())
+	assert.False(t, cfg.Timezone.IsZero())
+}
+
+// TestLocationInvalid tests that unknown time zones fail at load
+func TestLocationInvalid(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("TIMEZONE", "Mars/Olympus_Mons")
+
+	var cfg timeConfig
+	require.ErrorIs(t, config.Load(&cfg), config.ErrInvalidLocation)
+}
+
+// TestLocationZero tests that the zero value resolves to UTC
+func TestLocationZero(t *testing.T) {
+	var l config.Location
+	assert.True(t, l.IsZero())
+	assert.Equal(t, time.UTC, l.Location())
+}
+
+// TestDateAndTimeOfDay tests loading scheduling-style values from YAML
+func TestDateAndTimeOfDay(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	yamlFile := writeTempFile(t, tmpDir, "config.yaml", `maintenance:
+  date: 2025-01-31
+  start: "23:30"
+  end: 23:59:30`)
+
+	var cfg timeConfig
+	require.NoError(t, config.Load(&cfg, config.WithLocalYAML(yamlFile)))
+
+	assert.Equal(t, config.NewDate(2025, time.January, 31), cfg.Maintenance.Date)
+	assert.Equal(t, config.NewTimeOfDay(23, 30, 0), cfg.Maintenance.Start)
+	assert.Equal(t, config.NewTimeOfDay(23, 59, 30), cfg.Maintenance.End)
+
+	day := cfg.Maintenance.Date.In(time.UTC)
+	assert.Equal(t, time.Date(2025, time.January, 31, 23, 30, 0, 0, time.UTC), cfg.Maintenance.Start.On(day))
+	assert.Equal(t, 23*time.Hour+30*time.Minute, cfg.Maintenance.Start.SinceMidnight())
+
+	out, err := config.Dump(cfg.Maintenance, config.FormatJSON)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"date": "2025-01-31", "start": "23:30", "end": "23:59:30"}`, string(out))
+}
+
+// TestDateAndTimeOfDayInvalid tests that malformed values fail at load
+func TestDateAndTimeOfDayInvalid(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	tests := []struct {
+		env, value string
+		err        error
+	}{
+		{"MAINTENANCE__DATE", "2025-02-30", config.ErrInvalidDate},
+		{"MAINTENANCE__DATE", "2025-01-31T10:00:00Z", config.ErrInvalidDate},
+		{"MAINTENANCE__START", "25:00", config.ErrInvalidTimeOfDay},
+		{"MAINTENANCE__START", "noon", config.ErrInvalidTimeOfDay},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv(tt.env, tt.value)
+			var cfg timeConfig
+			require.ErrorIs(t, config.Load(&cfg), tt.err)
+		})
+	}
+}
+
+type timestampConfig struct {
+	StartsAt time.Time `koanf:"starts_at"`
+}
+
+// TestTimeLayouts tests decoding of plain time.Time fields
+func TestTimeLayouts(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+
+	t.Run("rfc3339", func(t *testing.T) {
+		t.Setenv("STARTS_AT", "2025-01-31T10:00:00+02:00")
+		var cfg timestampConfig
+		require.NoError(t, config.Load(&cfg))
+		assert.True(t, cfg.StartsAt.Equal(time.Date(2025, time.January, 31, 8, 0, 0, 0, time.UTC)))
+	})
+
+	t.Run("yaml timestamp", func(t *testing.T) {
+		yamlFile := writeTempFile(t, tmpDir, "config.yaml", `starts_at: 2025-01-31T10:00:00Z`)
+		var cfg timestampConfig
+		require.NoError(t, config.Load(&cfg, config.WithLocalYAML(yamlFile)))
+		assert.True(t, cfg.StartsAt.Equal(time.Date(2025, time.January, 31, 10, 0, 0, 0, time.UTC)))
+	})
+
+	t.Run("custom layouts", func(t *testing.T) {
+		t.Setenv("STARTS_AT", "31.01.2025 10:00")
+		var cfg timestampConfig
+		require.NoError(t, config.Load(&cfg, config.WithTimeLayouts(time.RFC3339, "02.01.2006 15:04")))
+		assert.True(t, cfg.StartsAt.Equal(time.Date(2025, time.January, 31, 10, 0, 0, 0, time.UTC)))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Setenv("STARTS_AT", "31.01.2025 10:00")
+		var cfg timestampConfig
+		require.ErrorIs(t, config.Load(&cfg), config.ErrInvalidTime)
+	})
+}
+
+// TestNativeDuration tests decoding of plain time.Duration fields
+func TestNativeDuration(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	t.Setenv("RETRY__BACKOFF", "1m30s")
+	yamlFile := writeTempFile(t, tmpDir, "config.yaml", `timeout: 30s
+retry:
+  backoff: 5s`)
+
+	var cfg struct {
+		Timeout time.Duration `koanf:"timeout"`
+		Retry   struct {
+			Backoff time.Duration `koanf:"backoff"`
+		} `koanf:"retry"`
+	}
+	require.NoError(t, config.Load(&cfg, config.WithLocalYAML(yamlFile)))
+
+	assert.Equal(t, 30*time.Second, cfg.Timeout)
+	assert.Equal(t, 90*time.Second, cfg.Retry.Backoff)
+}
+
+// TestDurationRoundTrip tests marshaling and unmarshaling of the Duration type
+func TestDurationRoundTrip(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	yamlFile := writeTempFile(t, tmpDir, "config.yaml", `timeout: 1m30s`)
+
+	var cfg struct {
+		Timeout config.Duration `koanf:"timeout" json:"timeout"`
+	}
+	require.NoError(t, config.Load(&cfg, config.WithLocalYAML(yamlFile)))
+	assert.Equal(t, 90*time.Second, cfg.Timeout.Duration())
+
+	b, err := json.Marshal(cfg)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"timeout": "1m30s"}`, string(b))
+
+	cfg.Timeout = 0
+	require.NoError(t, json.Unmarshal(b, &cfg))
+	assert.Equal(t, config.Duration(90*time.Second), cfg.Timeout)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"timeout": 1000000000}`), &cfg))
+	assert.Equal(t, time.Second, cfg.Timeout.Duration())
+
+	out, err := config.Dump(cfg, config.FormatYAML)
+	require.NoError(t, err)
+	assert.Equal(t, "timeout: 1s\n", string(out))
+
+	require.ErrorIs(t, json.Unmarshal([]byte(`{"timeout": "soon"}`), &cfg), config.ErrInvalidDuration)
+}
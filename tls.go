@@ -0,0 +1,82 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+var ErrInvalidTLS = errors.New("invalid tls config")
+
+// TLSConfig is a reusable TLS configuration section.
+type TLSConfig struct {
+	// CertFile is a path to a PEM-encoded certificate chain.
+	CertFile string `koanf:"cert_file"`
+	// KeyFile is a path to the PEM-encoded private key of the certificate.
+	KeyFile string `koanf:"key_file"`
+	// CAFile is a path to PEM-encoded CA certificates used to verify peers.
+	CAFile string `koanf:"ca_file"`
+	// InsecureSkipVerify disables verification of the peer certificate.
+	InsecureSkipVerify bool `koanf:"insecure_skip_verify"`
+	// MinVersion is the minimum TLS version: "1.0", "1.1", "1.2" or "1.3". Defaults to "1.2".
+	MinVersion string `koanf:"min_version"`
+}
+
+// Build loads the referenced files and returns the resulting *tls.Config.
+//
+// The CA certificates are used both as root CAs for verifying servers and as client CAs for verifying clients.
+func (c TLSConfig) Build() (*tls.Config, error) {
+	minVersion, err := tlsVersion(c.MinVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := new(tls.Config)
+	cfg.MinVersion = minVersion
+	cfg.InsecureSkipVerify = c.InsecureSkipVerify //nolint:gosec // explicit opt-in of the user
+
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return nil, fmt.Errorf("%w: cert_file and key_file must be set together", ErrInvalidTLS)
+	}
+
+	if c.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w: load key pair: %w", ErrInvalidTLS, err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w: read ca file: %w", ErrInvalidTLS, err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%w: no certificates found in %s", ErrInvalidTLS, c.CAFile)
+		}
+		cfg.RootCAs = pool
+		cfg.ClientCAs = pool
+	}
+
+	return cfg, nil
+}
+
+func tlsVersion(v string) (uint16, error) {
+	switch v {
+	case "", "1.2":
+		return tls.VersionTLS12, nil
+	case "1.0":
+		return tls.VersionTLS10, nil
+	case "1.1":
+		return tls.VersionTLS11, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	}
+
+	return 0, fmt.Errorf("%w: unsupported min_version %q", ErrInvalidTLS, v)
+}
@@ -0,0 +1,101 @@
+package config_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeSelfSigned writes a self-signed certificate and its key to dir and returns their paths
+func writeSelfSigned(t *testing.T, dir string) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "localhost"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile := writeTempFile(t, dir, "cert.pem", string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})))
+	keyFile := writeTempFile(t, dir, "key.pem", string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})))
+	return certFile, keyFile
+}
+
+// TestTLSConfigBuild tests building a *tls.Config from a loaded TLS section
+func TestTLSConfigBuild(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	certFile, keyFile := writeSelfSigned(t, tmpDir)
+	yamlFile := writeTempFile(t, tmpDir, "config.yaml", `tls:
+  cert_file: `+certFile+`
+  key_file: `+keyFile+`
+  ca_file: `+certFile+`
+  min_version: "1.3"`)
+
+	var cfg struct {
+		TLS config.TLSConfig `koanf:"tls"`
+	}
+	require.NoError(t, config.Load(&cfg, config.WithLocalYAML(yamlFile)))
+
+	tlsCfg, err := cfg.TLS.Build()
+	require.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS13), tlsCfg.MinVersion)
+	assert.Len(t, tlsCfg.Certificates, 1)
+	assert.NotNil(t, tlsCfg.RootCAs)
+	assert.NotNil(t, tlsCfg.ClientCAs)
+	assert.False(t, tlsCfg.InsecureSkipVerify)
+}
+
+// TestTLSConfigBuildDefaults tests the zero TLS section
+func TestTLSConfigBuildDefaults(t *testing.T) {
+	tlsCfg, err := config.TLSConfig{}.Build()
+	require.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS12), tlsCfg.MinVersion)
+	assert.Empty(t, tlsCfg.Certificates)
+	assert.Nil(t, tlsCfg.RootCAs)
+}
+
+// TestTLSConfigBuildErrors tests validation of the TLS section
+func TestTLSConfigBuildErrors(t *testing.T) {
+	tmpDir := t.TempDir()
+	certFile, _ := writeSelfSigned(t, tmpDir)
+	notPEM := writeTempFile(t, tmpDir, "not.pem", "garbage")
+
+	tests := []struct {
+		name string
+		cfg  config.TLSConfig
+	}{
+		{"cert without key", config.TLSConfig{CertFile: certFile}},
+		{"missing key file", config.TLSConfig{CertFile: certFile, KeyFile: tmpDir + "/missing.pem"}},
+		{"missing ca file", config.TLSConfig{CAFile: tmpDir + "/missing.pem"}},
+		{"invalid ca file", config.TLSConfig{CAFile: notPEM}},
+		{"invalid min version", config.TLSConfig{MinVersion: "2.0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.cfg.Build()
+			require.ErrorIs(t, err, config.ErrInvalidTLS)
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package config_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-core-fx/config"
+	"github.com/knadh/koanf/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWithTransform tests normalizing the merged configuration before unmarshaling
+func TestWithTransform(t *testing.T) {
+	environ := map[string]string{"DATABASE__HOST": "  DB.Example.COM ", "SERVER__PORT": "8080"}
+	normalize := config.WithTransform(func(k *koanf.Koanf) error {
+		return k.Set("database.host", strings.ToLower(strings.TrimSpace(k.String("database.host"))))
+	})
+	derive := config.WithTransform(func(k *koanf.Koanf) error {
+		if !k.Exists("database.port") {
+			return k.Set("database.port", k.Int("server.port")+1)
+		}
+		return nil
+	})
+
+	var c TestConfig
+	require.NoError(t, config.Load(&c, config.WithEnviron(environ), normalize, derive))
+	assert.Equal(t, "db.example.com", c.Database.Host)
+	assert.Equal(t, 8081, c.Database.Port)
+
+	errBroken := errors.New("broken")
+	err := config.Load(&c, config.WithEnviron(nil), config.WithTransform(func(*koanf.Koanf) error { return errBroken }))
+	require.ErrorIs(t, err, errBroken)
+}
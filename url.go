@@ -0,0 +1,119 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+var ErrInvalidURL = errors.New("invalid url")
+
+// URL is an absolute URL validated when the configuration is loaded.
+type URL struct {
+	u *url.URL
+}
+
+// ParseURL parses an absolute URL with a scheme and a host or path.
+// Opaque URLs such as "localhost:8080" are rejected.
+func ParseURL(s string) (URL, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return URL{}, fmt.Errorf("%w: %w", ErrInvalidURL, err)
+	}
+
+	if u.Scheme == "" {
+		return URL{}, fmt.Errorf("%w: %q: missing scheme", ErrInvalidURL, s)
+	}
+
+	// host:port without a scheme parses as scheme "host" and opaque "port"
+	if u.Opaque != "" {
+		return URL{}, fmt.Errorf("%w: %q: missing scheme or host", ErrInvalidURL, s)
+	}
+
+	if u.Host == "" && u.Path == "" {
+		return URL{}, fmt.Errorf("%w: %q: missing host", ErrInvalidURL, s)
+	}
+
+	return URL{u: u}, nil
+}
+
+// URL returns a copy of the parsed URL, or nil if the value is not set.
+func (u URL) URL() *url.URL {
+	if u.u == nil {
+		return nil
+	}
+
+	c := *u.u
+	return &c
+}
+
+// IsZero reports whether the value is not set.
+func (u URL) IsZero() bool {
+	return u.u == nil
+}
+
+// Scheme returns the URL scheme.
+func (u URL) Scheme() string {
+	if u.u == nil {
+		return ""
+	}
+
+	return u.u.Scheme
+}
+
+// Host returns the host, including the port if present.
+func (u URL) Host() string {
+	if u.u == nil {
+		return ""
+	}
+
+	return u.u.Host
+}
+
+// Hostname returns the host without the port.
+func (u URL) Hostname() string {
+	if u.u == nil {
+		return ""
+	}
+
+	return u.u.Hostname()
+}
+
+// Port returns the port, or an empty string if not present.
+func (u URL) Port() string {
+	if u.u == nil {
+		return ""
+	}
+
+	return u.u.Port()
+}
+
+// String returns the URL as a string.
+func (u URL) String() string {
+	if u.u == nil {
+		return ""
+	}
+
+	return u.u.String()
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (u URL) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (u *URL) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*u = URL{}
+		return nil
+	}
+
+	parsed, err := ParseURL(string(text))
+	if err != nil {
+		return err
+	}
+
+	*u = parsed
+	return nil
+}
@@ -0,0 +1,55 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type urlConfig struct {
+	Endpoint config.URL `koanf:"endpoint"`
+}
+
+// TestURL tests loading and helpers of the URL type
+func TestURL(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("ENDPOINT", "https://api.example.com:8443/v1?x=1")
+
+	var cfg urlConfig
+	require.NoError(t, config.Load(&cfg))
+
+	assert.Equal(t, "https", cfg.Endpoint.Scheme())
+	assert.Equal(t, "api.example.com:8443", cfg.Endpoint.Host())
+	assert.Equal(t, "api.example.com", cfg.Endpoint.Hostname())
+	assert.Equal(t, "8443", cfg.Endpoint.Port())
+	assert.Equal(t, "/v1", cfg.Endpoint.URL().Path)
+	assert.False(t, cfg.Endpoint.IsZero())
+
+	out, err := config.Dump(cfg, config.FormatJSON)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"endpoint": "https://api.example.com:8443/v1?x=1"}`, string(out))
+}
+
+// TestURLInvalid tests that invalid URLs fail at load
+func TestURLInvalid(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	for _, v := range []string{"api.example.com", "://bad", "https://", "localhost:8080", "db.internal:5432/app"} {
+		t.Run(v, func(t *testing.T) {
+			t.Setenv("ENDPOINT", v)
+			var cfg urlConfig
+			require.ErrorIs(t, config.Load(&cfg), config.ErrInvalidURL)
+		})
+	}
+}
+
+// TestURLZero tests the zero value of the URL type
+func TestURLZero(t *testing.T) {
+	var u config.URL
+	assert.True(t, u.IsZero())
+	assert.Nil(t, u.URL())
+	assert.Empty(t, u.String())
+	assert.Empty(t, u.Scheme())
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+var ErrVerification = errors.New("verification failed")
+
+// Document is a serialized YAML or JSON configuration document fetched by a RawSource,
+// with its detached signature, if any.
+type Document struct {
+	Data      []byte
+	Signature []byte
+}
+
+// Parse parses the document as YAML, or JSON, which is a subset of YAML.
+//
+// Unlike local files, custom tags such as !include and !file are not resolved.
+func (d Document) Parse() (map[string]any, error) {
+	var m map[string]any
+	if err := yaml.Unmarshal(d.Data, &m); err != nil {
+		return nil, fmt.Errorf("parse: %w", err)
+	}
+
+	return m, nil
+}
+
+// RawSource is a Source that can return the document it loads before parsing,
+// so it can be verified with WithVerification. These sources are loaded with LoadRaw and Document.Parse,
+// so SourceInfo.Bytes reports the size of the document.
+type RawSource interface {
+	Source
+	// LoadRaw returns the raw document of the source.
+	LoadRaw(ctx context.Context) (Document, error)
+}
+
+// Verifier checks the integrity of a document fetched by a RawSource.
+type Verifier func(doc Document) error
+
+// VerifySHA256 accepts documents whose SHA-256 checksum is the given hex-encoded sum.
+func VerifySHA256(sum string) Verifier {
+	return func(doc Document) error {
+		want, err := hex.DecodeString(strings.TrimSpace(sum))
+		if err != nil {
+			return fmt.Errorf("%w: invalid checksum: %w", ErrVerification, err)
+		}
+
+		got := sha256.Sum256(doc.Data)
+		if subtle.ConstantTimeCompare(got[:], want) != 1 {
+			return fmt.Errorf("%w: checksum mismatch", ErrVerification)
+		}
+
+		return nil
+	}
+}
+
+// VerifyEd25519 accepts documents with a valid detached Ed25519 signature by the given public key.
+func VerifyEd25519(publicKey ed25519.PublicKey) Verifier {
+	return func(doc Document) error {
+		if len(publicKey) != ed25519.PublicKeySize {
+			return fmt.Errorf("%w: invalid public key", ErrVerification)
+		}
+		if len(doc.Signature) == 0 {
+			return fmt.Errorf("%w: missing signature", ErrVerification)
+		}
+		if !ed25519.Verify(publicKey, doc.Data, doc.Signature) {
+			return fmt.Errorf("%w: invalid signature", ErrVerification)
+		}
+
+		return nil
+	}
+}
+
+// loadRaw loads the raw document of src, verifies it if verify is not nil, and parses it.
+// It also returns the size of the document.
+func loadRaw(ctx context.Context, src RawSource, verify Verifier) (map[string]any, int, error) {
+	doc, err := src.LoadRaw(ctx)
+	if err != nil {
+		return nil, 0, err //nolint:wrapcheck // wrapped by the caller
+	}
+
+	if verify != nil {
+		if err := verify(doc); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	m, err := doc.Parse()
+	return m, len(doc.Data), err
+}
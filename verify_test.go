@@ -0,0 +1,86 @@
+package config_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rawSource returns a fixed document.
+type rawSource struct {
+	doc config.Document
+}
+
+func (s rawSource) Name() string { return "remote" }
+
+func (s rawSource) Load(context.Context) (map[string]any, error) { return s.doc.Parse() }
+
+func (s rawSource) LoadRaw(context.Context) (config.Document, error) { return s.doc, nil }
+
+// whitespaceSigned returns a document signed with a signature ending in a whitespace byte,
+// which must not be trimmed before verification.
+func whitespaceSigned(t *testing.T, priv ed25519.PrivateKey) config.Document {
+	t.Helper()
+
+	for i := range 10000 {
+		data := []byte(fmt.Sprintf("database:\n  host: remote\n# %d\n", i))
+		sig := ed25519.Sign(priv, data)
+		if bytes.ContainsAny(sig[len(sig)-1:], " \t\n\v\f\r") {
+			return config.Document{Data: data, Signature: sig}
+		}
+	}
+	t.Fatal("no signature ending in whitespace")
+
+	return config.Document{Data: nil, Signature: nil}
+}
+
+// TestVerification tests accepting remote documents by checksum and signature
+func TestVerification(t *testing.T) {
+	data := []byte("database:\n  host: remote\n")
+	sum := sha256.Sum256(data)
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	signed := rawSource{doc: config.Document{Data: data, Signature: ed25519.Sign(priv, data)}}
+	tampered := rawSource{doc: config.Document{Data: []byte("database:\n  host: evil\n"), Signature: signed.doc.Signature}}
+
+	tests := map[string]struct {
+		src    config.Source
+		verify config.Verifier
+		ok     bool
+	}{
+		"checksum":          {src: signed, verify: config.VerifySHA256(hex.EncodeToString(sum[:])), ok: true},
+		"checksum mismatch": {src: tampered, verify: config.VerifySHA256(hex.EncodeToString(sum[:])), ok: false},
+		"signature":         {src: signed, verify: config.VerifyEd25519(pub), ok: true},
+		"invalid signature": {src: tampered, verify: config.VerifyEd25519(pub), ok: false},
+		"whitespace byte":   {src: rawSource{doc: whitespaceSigned(t, priv)}, verify: config.VerifyEd25519(pub), ok: true},
+		"missing signature": {src: rawSource{doc: config.Document{Data: data, Signature: nil}}, verify: config.VerifyEd25519(pub), ok: false},
+		"not a raw source":  {src: &fakeSource{data: map[string]any{}}, verify: config.VerifyEd25519(pub), ok: false},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var c TestConfig
+			err := config.Load(&c,
+				config.WithFS(fstest.MapFS{}),
+				config.WithEnviron(nil),
+				config.WithSource(tt.src),
+				config.WithVerification("remote", tt.verify),
+			)
+			if !tt.ok {
+				require.ErrorIs(t, err, config.ErrVerification)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "remote", c.Database.Host)
+		})
+	}
+}
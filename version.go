@@ -0,0 +1,171 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+var (
+	ErrInvalidVersion           = errors.New("invalid version")
+	ErrInvalidVersionConstraint = errors.New("invalid version constraint")
+)
+
+// Version is a semantic version such as "1.4.2" or "v2.0.0-rc.1", validated when the configuration is loaded.
+type Version struct {
+	v *semver.Version
+}
+
+// ParseVersion parses a semantic version.
+func ParseVersion(s string) (Version, error) {
+	v, err := semver.NewVersion(s)
+	if err != nil {
+		return Version{}, fmt.Errorf("%w: %q: %w", ErrInvalidVersion, s, err)
+	}
+
+	return Version{v: v}, nil
+}
+
+// Semver returns the underlying version, or nil if the value is not set.
+func (v Version) Semver() *semver.Version {
+	return v.v
+}
+
+// IsZero reports whether the value is not set.
+func (v Version) IsZero() bool {
+	return v.v == nil
+}
+
+// Major returns the major version.
+func (v Version) Major() uint64 {
+	if v.v == nil {
+		return 0
+	}
+
+	return v.v.Major()
+}
+
+// Minor returns the minor version.
+func (v Version) Minor() uint64 {
+	if v.v == nil {
+		return 0
+	}
+
+	return v.v.Minor()
+}
+
+// Patch returns the patch version.
+func (v Version) Patch() uint64 {
+	if v.v == nil {
+		return 0
+	}
+
+	return v.v.Patch()
+}
+
+// Compare returns -1, 0 or 1 if v is less than, equal to or greater than o.
+// An unset version is less than any set version.
+func (v Version) Compare(o Version) int {
+	switch {
+	case v.v == nil && o.v == nil:
+		return 0
+	case v.v == nil:
+		return -1
+	case o.v == nil:
+		return 1
+	}
+
+	return v.v.Compare(o.v)
+}
+
+// String returns the version as originally written.
+func (v Version) String() string {
+	if v.v == nil {
+		return ""
+	}
+
+	return v.v.Original()
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (v Version) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (v *Version) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*v = Version{}
+		return nil
+	}
+
+	parsed, err := ParseVersion(string(text))
+	if err != nil {
+		return err
+	}
+
+	*v = parsed
+	return nil
+}
+
+// VersionConstraint is a set of semantic version constraints such as ">= 1.2, < 2.0" or "^1.4",
+// validated when the configuration is loaded.
+type VersionConstraint struct {
+	c   *semver.Constraints
+	raw string
+}
+
+// ParseVersionConstraint parses version constraints.
+func ParseVersionConstraint(s string) (VersionConstraint, error) {
+	c, err := semver.NewConstraint(s)
+	if err != nil {
+		return VersionConstraint{}, fmt.Errorf("%w: %q: %w", ErrInvalidVersionConstraint, s, err)
+	}
+
+	return VersionConstraint{c: c, raw: s}, nil
+}
+
+// Check reports whether the version satisfies the constraints.
+// An unset constraint is satisfied by any version; an unset version satisfies no set constraint.
+func (c VersionConstraint) Check(v Version) bool {
+	if c.c == nil {
+		return true
+	}
+	if v.v == nil {
+		return false
+	}
+
+	return c.c.Check(v.v)
+}
+
+// IsZero reports whether the value is not set.
+func (c VersionConstraint) IsZero() bool {
+	return c.c == nil
+}
+
+// String returns the constraints as originally written.
+func (c VersionConstraint) String() string {
+	return c.raw
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (c VersionConstraint) MarshalText() ([]byte, error) {
+	return []byte(c.raw), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (c *VersionConstraint) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*c = VersionConstraint{}
+		return nil
+	}
+
+	parsed, err := ParseVersionConstraint(string(text))
+	if err != nil {
+		return err
+	}
+
+	*c = parsed
+	return nil
+}
@@ -0,0 +1,65 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type versionConfig struct {
+	MinPeerVersion config.Version           `koanf:"min_peer_version"`
+	Migrations     config.VersionConstraint `koanf:"migrations"`
+}
+
+// TestVersion tests loading of semantic versions and constraints
+func TestVersion(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	yamlFile := writeTempFile(t, tmpDir, "config.yaml", `min_peer_version: v1.4.2
+migrations: ">= 1.2, < 2.0"`)
+
+	var cfg versionConfig
+	require.NoError(t, config.Load(&cfg, config.WithLocalYAML(yamlFile)))
+
+	assert.Equal(t, uint64(1), cfg.MinPeerVersion.Major())
+	assert.Equal(t, uint64(4), cfg.MinPeerVersion.Minor())
+	assert.Equal(t, uint64(2), cfg.MinPeerVersion.Patch())
+	assert.Equal(t, "v1.4.2", cfg.MinPeerVersion.String())
+	assert.True(t, cfg.Migrations.Check(cfg.MinPeerVersion))
+
+	v2, err := config.ParseVersion("2.0.0")
+	require.NoError(t, err)
+	assert.False(t, cfg.Migrations.Check(v2))
+	assert.Equal(t, -1, cfg.MinPeerVersion.Compare(v2))
+	assert.Equal(t, 1, v2.Compare(config.Version{}))
+
+	out, err := config.Dump(cfg, config.FormatJSON)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"min_peer_version": "v1.4.2", "migrations": ">= 1.2, < 2.0"}`, string(out))
+}
+
+// TestVersionInvalid tests that malformed versions fail at load
+func TestVersionInvalid(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	t.Run("version", func(t *testing.T) {
+		t.Setenv("MIN_PEER_VERSION", "one.two")
+		var cfg versionConfig
+		require.ErrorIs(t, config.Load(&cfg), config.ErrInvalidVersion)
+	})
+
+	t.Run("constraint", func(t *testing.T) {
+		t.Setenv("MIGRATIONS", ">=> 1")
+		var cfg versionConfig
+		require.ErrorIs(t, config.Load(&cfg), config.ErrInvalidVersionConstraint)
+	})
+}
+
+// TestVersionConstraintZero tests the zero constraint
+func TestVersionConstraintZero(t *testing.T) {
+	v, err := config.ParseVersion("0.1.0")
+	require.NoError(t, err)
+	assert.True(t, config.VersionConstraint{}.Check(v))
+}
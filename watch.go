@@ -0,0 +1,235 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ChangeEvent describes a reload that changed the configuration.
+type ChangeEvent[T any] struct {
+	// Old is the configuration before the reload.
+	Old T
+	// New is the configuration after the reload.
+	New T
+	// Changes are all changed keys, including secrets.
+	Changes []Change
+}
+
+// Watcher keeps the latest configuration of type T and reloads it on demand or periodically.
+//
+// Reloads that change only Secret values or fields tagged with `secret:"true"` are reported
+// to OnSecretRotated callbacks, so connection pools can re-authenticate without treating them
+// as a full configuration change.
+type Watcher[T any] struct {
+	opts      []Option
+	tags      []string
+	reloadMu  sync.Mutex
+	overrides map[string]any
+
+	mu      sync.RWMutex
+	current T
+
+	cbMu            sync.Mutex
+	onChange        []func(ChangeEvent[T])
+	onSecretRotated []func([]Change)
+}
+
+// NewWatcher loads the configuration with the given options and returns a watcher holding it.
+// The same options are used for every reload.
+func NewWatcher[T any](ctx context.Context, opts ...Option) (*Watcher[T], error) {
+	options := newOptions()
+	options.apply(opts...)
+
+	w := &Watcher[T]{
+		opts:      opts,
+		tags:      options.tags,
+		reloadMu:  sync.Mutex{},
+		overrides: map[string]any{},
+
+		mu:      sync.RWMutex{},
+		current: *new(T),
+
+		cbMu:            sync.Mutex{},
+		onChange:        nil,
+		onSecretRotated: nil,
+	}
+
+	if err := LoadContext(ctx, &w.current, opts...); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// Get returns the current configuration.
+func (w *Watcher[T]) Get() T {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.current
+}
+
+// OnChange registers a callback fired after a reload changed any non-secret key.
+func (w *Watcher[T]) OnChange(fn func(ChangeEvent[T])) {
+	w.cbMu.Lock()
+	defer w.cbMu.Unlock()
+
+	w.onChange = append(w.onChange, fn)
+}
+
+// OnSecretRotated registers a callback fired after a reload changed any secret key.
+// It receives only the secret changes, with values masked.
+func (w *Watcher[T]) OnSecretRotated(fn func([]Change)) {
+	w.cbMu.Lock()
+	defer w.cbMu.Unlock()
+
+	w.onSecretRotated = append(w.onSecretRotated, fn)
+}
+
+// Reload loads the configuration again and notifies callbacks if it changed.
+// On error, the current configuration is kept.
+func (w *Watcher[T]) Reload(ctx context.Context) error {
+	w.reloadMu.Lock()
+	defer w.reloadMu.Unlock()
+
+	return w.reload(ctx)
+}
+
+// Set overrides the given key at runtime, on top of all sources, and reloads the configuration.
+// If the reload fails, e.g. because the value does not decode, the override is discarded.
+func (w *Watcher[T]) Set(ctx context.Context, key string, value any) error {
+	w.reloadMu.Lock()
+	defer w.reloadMu.Unlock()
+
+	prev, had := w.overrides[key]
+	w.overrides[key] = value
+
+	if err := w.reload(ctx); err != nil {
+		if had {
+			w.overrides[key] = prev
+		} else {
+			delete(w.overrides, key)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Unset removes a runtime override set with Set and reloads the configuration.
+func (w *Watcher[T]) Unset(ctx context.Context, key string) error {
+	w.reloadMu.Lock()
+	defer w.reloadMu.Unlock()
+
+	prev, had := w.overrides[key]
+	if !had {
+		return nil
+	}
+	delete(w.overrides, key)
+
+	if err := w.reload(ctx); err != nil {
+		w.overrides[key] = prev
+		return err
+	}
+
+	return nil
+}
+
+// DryRun loads a candidate configuration with opts added to the watcher's options, e.g. WithLocalYAML
+// with a new file or WithEnviron, and returns how it differs from the current configuration.
+// Nothing is applied: callbacks are not notified, OnLoad hooks, WithProvenance, source caches and the circuit
+// breaker are not updated, and the configuration is not logged.
+func (w *Watcher[T]) DryRun(ctx context.Context, opts ...Option) ([]Change, error) {
+	w.reloadMu.Lock()
+	opts = append(w.options(), append(opts, withDryRun())...)
+	w.reloadMu.Unlock()
+
+	var next T
+	if err := LoadContext(ctx, &next, opts...); err != nil {
+		return nil, err
+	}
+
+	changes, err := Diff(w.Get(), next, withTags(w.tags))
+	if err != nil {
+		return nil, fmt.Errorf("diff: %w", err)
+	}
+
+	return changes, nil
+}
+
+// options returns the options of the next load, including runtime overrides. Callers hold reloadMu.
+func (w *Watcher[T]) options() []Option {
+	return append(slices.Clone(w.opts), withOverrides(maps.Clone(w.overrides)))
+}
+
+func (w *Watcher[T]) reload(ctx context.Context) error {
+	opts := w.options()
+
+	var next T
+	if err := LoadContext(ctx, &next, opts...); err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	prev := w.current
+	changes, err := Diff(prev, next, withTags(w.tags))
+	if err != nil {
+		w.mu.Unlock()
+		return fmt.Errorf("diff: %w", err)
+	}
+	w.current = next
+	w.mu.Unlock()
+
+	w.notify(ChangeEvent[T]{Old: prev, New: next, Changes: changes})
+
+	return nil
+}
+
+// Poll reloads the configuration every interval until ctx is done.
+// Reload errors do not stop polling; observe them with OnLoad.
+func (w *Watcher[T]) Poll(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_ = w.Reload(ctx)
+		}
+	}
+}
+
+func (w *Watcher[T]) notify(e ChangeEvent[T]) {
+	var secrets []Change
+	full := false
+	for _, c := range e.Changes {
+		if c.Secret {
+			secrets = append(secrets, c)
+		} else {
+			full = true
+		}
+	}
+
+	w.cbMu.Lock()
+	onChange := w.onChange
+	onSecretRotated := w.onSecretRotated
+	w.cbMu.Unlock()
+
+	if full {
+		for _, fn := range onChange {
+			fn(e)
+		}
+	}
+
+	if len(secrets) > 0 {
+		for _, fn := range onSecretRotated {
+			fn(secrets)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package config_test
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type watchConfig struct {
+	Database struct {
+		Host     string        `koanf:"host"`
+		Password config.Secret `koanf:"password"`
+	} `koanf:"database"`
+}
+
+// TestWatcherReload tests change and secret rotation notifications
+func TestWatcherReload(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	yamlFile := writeTempFile(t, tmpDir, "config.yaml", "database:\n  host: a\n  password: p1\n")
+
+	w, err := config.NewWatcher[watchConfig](t.Context(), config.WithLocalYAML(yamlFile))
+	require.NoError(t, err)
+	assert.Equal(t, "a", w.Get().Database.Host)
+
+	var changes []config.ChangeEvent[watchConfig]
+	var rotations [][]config.Change
+	w.OnChange(func(e config.ChangeEvent[watchConfig]) { changes = append(changes, e) })
+	w.OnSecretRotated(func(c []config.Change) { rotations = append(rotations, c) })
+
+	// no changes
+	require.NoError(t, w.Reload(t.Context()))
+	assert.Empty(t, changes)
+	assert.Empty(t, rotations)
+
+	// secret only
+	writeTempFile(t, tmpDir, "config.yaml", "database:\n  host: a\n  password: p2\n")
+	require.NoError(t, w.Reload(t.Context()))
+	assert.Empty(t, changes)
+	require.Len(t, rotations, 1)
+	assert.Equal(t, "database.password", rotations[0][0].Key)
+	assert.Equal(t, config.Secret("p2"), w.Get().Database.Password)
+
+	// regular change
+	writeTempFile(t, tmpDir, "config.yaml", "database:\n  host: b\n  password: p2\n")
+	require.NoError(t, w.Reload(t.Context()))
+	require.Len(t, changes, 1)
+	assert.Equal(t, "a", changes[0].Old.Database.Host)
+	assert.Equal(t, "b", changes[0].New.Database.Host)
+	assert.Len(t, rotations, 1)
+}
+
+// TestWatcherReloadError tests that a failed reload keeps the current configuration
+func TestWatcherReloadError(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	yamlFile := writeTempFile(t, tmpDir, "config.yaml", "database:\n  host: a\n")
+
+	w, err := config.NewWatcher[watchConfig](t.Context(), config.WithLocalYAML(yamlFile))
+	require.NoError(t, err)
+
+	writeTempFile(t, tmpDir, "config.yaml", "invalid: yaml: content: [")
+	require.Error(t, w.Reload(t.Context()))
+	assert.Equal(t, "a", w.Get().Database.Host)
+}
+
+// TestWatcherSet tests runtime overrides taking precedence over all sources
+func TestWatcherSet(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	t.Setenv("DATABASE__HOST", "env")
+	yamlFile := writeTempFile(t, tmpDir, "config.yaml", "database:\n  host: a\n")
+
+	var p config.Provenance
+	w, err := config.NewWatcher[watchConfig](t.Context(), config.WithLocalYAML(yamlFile), config.WithProvenance(&p))
+	require.NoError(t, err)
+	assert.Equal(t, "env", w.Get().Database.Host)
+
+	var changes []config.ChangeEvent[watchConfig]
+	w.OnChange(func(e config.ChangeEvent[watchConfig]) { changes = append(changes, e) })
+
+	require.NoError(t, w.Set(t.Context(), "database.host", "runtime"))
+	assert.Equal(t, "runtime", w.Get().Database.Host)
+	assert.Equal(t, config.SourceRuntime, p["database.host"].Source)
+	require.Len(t, changes, 1)
+	assert.Equal(t, "env", changes[0].Old.Database.Host)
+
+	// the override survives reloads
+	require.NoError(t, w.Reload(t.Context()))
+	assert.Equal(t, "runtime", w.Get().Database.Host)
+
+	require.NoError(t, w.Unset(t.Context(), "database.host"))
+	assert.Equal(t, "env", w.Get().Database.Host)
+	assert.Len(t, changes, 2)
+}
+
+// TestWatcherSetError tests that an invalid override is discarded
+func TestWatcherSetError(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+
+	w, err := config.NewWatcher[timeConfig](t.Context())
+	require.NoError(t, err)
+
+	require.Error(t, w.Set(t.Context(), "timezone", "Nowhere/City"))
+	require.NoError(t, w.Reload(t.Context()))
+}
+
+// TestWatcherDryRun tests reporting changes of a candidate configuration without applying them
+func TestWatcherDryRun(t *testing.T) {
+	files := fstest.MapFS{
+		"config.yaml": {Data: []byte("database:\n  host: a\n  password: old\n")},
+		"next.yaml":   {Data: []byte("database:\n  host: b\n  password: new\n")},
+	}
+
+	loads := 0
+	w, err := config.NewWatcher[watchConfig](t.Context(),
+		config.WithFS(files), config.WithLocalYAML("config.yaml"), config.WithEnviron(nil),
+		config.OnLoad(func(config.LoadInfo) { loads++ }),
+	)
+	require.NoError(t, err)
+	w.OnChange(func(config.ChangeEvent[watchConfig]) { t.Error("dry run notified callbacks") })
+
+	changes, err := w.DryRun(t.Context(), config.WithLocalYAML("next.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, []config.Change{
+		{Key: "database.host", Old: "a", New: "b", Secret: false},
+		{Key: "database.password", Old: "******", New: "******", Secret: true},
+	}, changes)
+	assert.Equal(t, "a", w.Get().Database.Host)
+	assert.Equal(t, 1, loads)
+
+	changes, err = w.DryRun(t.Context(), config.WithEnviron(map[string]string{"DATABASE__HOST": "env"}))
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, "env", changes[0].New)
+}
+
+// TestWatcherDryRunSideEffects tests that dry runs neither update source caches and breakers nor log
+func TestWatcherDryRunSideEffects(t *testing.T) {
+	cacheDir := t.TempDir()
+	breaker := config.NewCircuitBreaker(1, config.ConstantBackoff(time.Hour, 1))
+	var logs bytes.Buffer
+
+	w, err := config.NewWatcher[watchConfig](t.Context(),
+		config.WithFS(fstest.MapFS{"config.yaml": {Data: []byte("database:\n  host: a\n")}}),
+		config.WithLocalYAML("config.yaml"),
+		config.WithEnviron(nil),
+		config.WithSourceCache(cacheDir),
+		config.WithCircuitBreaker(breaker),
+		config.WithLogger(slog.New(slog.NewTextHandler(&logs, nil))),
+	)
+	require.NoError(t, err)
+	logs.Reset()
+
+	src := &fakeSource{data: map[string]any{"database": map[string]any{"host": "remote"}}}
+	changes, err := w.DryRun(t.Context(), config.WithSource(src))
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	entries, err := os.ReadDir(cacheDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	_, err = w.DryRun(t.Context(), config.WithSource(&fakeSource{failures: 1}))
+	require.ErrorIs(t, err, errUnavailable)
+	assert.Equal(t, config.BreakerClosed, breaker.State("remote"))
+
+	assert.NotContains(t, logs.String(), "config loaded")
+}
@@ -0,0 +1,390 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	kmaps "github.com/knadh/koanf/maps"
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	yamlMergeKey   = "<<"
+	yamlIncludeTag = "!include"
+	yamlEnvTag     = "!env"
+	yamlFileTag    = "!file"
+	yamlSecretTag  = "!secret"
+)
+
+var (
+	ErrDuplicateKey = errors.New("duplicate key")
+	ErrInclude      = errors.New("invalid include")
+	ErrEnvNotSet    = errors.New("environment variable not set")
+	ErrFileTag      = errors.New("invalid file reference")
+	ErrSecretRef    = errors.New("invalid secret reference")
+)
+
+// yamlParser is a koanf.Parser for YAML files of one or more documents separated by `---`,
+// merged in order so later documents override earlier ones.
+//
+// Values tagged with `!include path` are replaced by the contents of the YAML file at path,
+// relative to the including file, values tagged with `!file path` by the contents of the file at path
+// as a string, values tagged with `!env NAME` by the environment variable NAME and values tagged with
+// `!secret scheme:ref` by the secret resolved by the resolver registered for scheme.
+type yamlParser struct {
+	logger *slog.Logger
+	// warnDuplicates logs duplicate keys and keeps the last value instead of failing.
+	warnDuplicates bool
+	// path is the parsed file, included files are resolved relative to it.
+	path string
+	read func(path string) ([]byte, error)
+	// lookupEnv resolves !env values, requireEnv fails on unset variables instead of using null.
+	lookupEnv  func(name string) (string, bool)
+	requireEnv bool
+	// resolveSecret resolves !secret references.
+	resolveSecret func(ref string) (string, error)
+	// recordOrder records the key order of a resolved document for OrderedMap fields.
+	recordOrder func(node *yaml.Node)
+}
+
+func (o *options) yamlParser(ctx context.Context, path string) yamlParser {
+	return yamlParser{
+		logger:         o.logger,
+		warnDuplicates: o.warnDuplicates,
+		path:           path,
+		read: func(path string) ([]byte, error) {
+			return o.fileProvider(path).ReadBytes() //nolint:wrapcheck // wrapped by the caller
+		},
+		lookupEnv:  o.lookupEnv,
+		requireEnv: o.requireEnv,
+		resolveSecret: func(ref string) (string, error) {
+			return resolveSecret(ctx, o.secretResolvers, ref)
+		},
+		recordOrder: func(node *yaml.Node) { o.recordKeyOrder(node, nil) },
+	}
+}
+
+func (p yamlParser) Unmarshal(b []byte) (map[string]any, error) {
+	out := map[string]any{}
+
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	for i := 1; ; i++ {
+		var node yaml.Node
+		err := dec.Decode(&node)
+		if errors.Is(err, io.EOF) {
+			return out, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %w", i, err)
+		}
+
+		if err := p.resolveTags(&node, p.path, []string{p.path}, collectAnchors(&node, nil)); err != nil {
+			return nil, fmt.Errorf("document %d: %w", i, err)
+		}
+
+		if err := p.checkDuplicates(&node, nil); err != nil {
+			return nil, fmt.Errorf("document %d: %w", i, err)
+		}
+		p.recordOrder(&node)
+
+		var doc map[string]any
+		if err := node.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("document %d: %w", i, err)
+		}
+
+		kmaps.Merge(doc, out)
+	}
+}
+
+func (p yamlParser) Marshal(m map[string]any) ([]byte, error) {
+	return yaml.Marshal(m) //nolint:wrapcheck // passed through to koanf
+}
+
+// checkDuplicates reports keys defined twice in the same mapping. When warning, earlier
+// definitions are dropped from the node so the last one wins.
+func (p yamlParser) checkDuplicates(node *yaml.Node, path []string) error {
+	if node.Kind != yaml.MappingNode {
+		for _, child := range node.Content {
+			if err := p.checkDuplicates(child, path); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	lines := map[string]int{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i]
+		if key.Value == yamlMergeKey {
+			continue
+		}
+
+		if line, ok := lines[key.Value]; ok {
+			name := strings.Join(append(slices.Clip(path), key.Value), ".")
+			if !p.warnDuplicates {
+				return fmt.Errorf("%w: %s at line %d, already defined at line %d", ErrDuplicateKey, name, key.Line, line)
+			}
+
+			p.logger.Warn("duplicate yaml key, using the last value",
+				slog.String("key", name), slog.Int("line", key.Line), slog.Int("previous_line", line))
+			node.Content = dropPair(node.Content, key.Value, i)
+			i -= 2
+		}
+		lines[key.Value] = key.Line
+
+		if err := p.checkDuplicates(node.Content[i+1], append(slices.Clip(path), key.Value)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// dropPair removes the first key-value pair named key before index end.
+func dropPair(content []*yaml.Node, key string, end int) []*yaml.Node {
+	for i := 0; i < end; i += 2 {
+		if content[i].Value == key {
+			return append(content[:i], content[i+2:]...)
+		}
+	}
+
+	return content
+}
+
+// resolveTags replaces nodes with custom tags by their values. Included files are resolved
+// relative to file, stack holds the chain of including files and anchors the anchored nodes
+// of the including files, which included files may reference.
+func (p yamlParser) resolveTags(node *yaml.Node, file string, stack []string, anchors []*yaml.Node) error {
+	anchor := node.Anchor
+
+	var err error
+	switch node.Tag {
+	case yamlIncludeTag:
+		err = p.include(node, file, stack, anchors)
+	case yamlEnvTag:
+		err = p.env(node)
+	case yamlFileTag:
+		err = p.file(node, file)
+	case yamlSecretTag:
+		err = p.secret(node)
+	default:
+		for _, child := range node.Content {
+			if err := p.resolveTags(child, file, stack, anchors); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// replaced nodes keep their anchor, so included files can still reference them
+	node.Anchor = anchor
+
+	return err
+}
+
+// include replaces the node by the document of the included file, failing on include cycles.
+func (p yamlParser) include(node *yaml.Node, file string, stack []string, anchors []*yaml.Node) error {
+	if node.Kind != yaml.ScalarNode || node.Value == "" {
+		return fmt.Errorf("%w: line %d: expected a file path", ErrInclude, node.Line)
+	}
+
+	target := relativeTo(file, node.Value)
+	if slices.Contains(stack, target) {
+		return fmt.Errorf("%w: line %d: include cycle: %s -> %s", ErrInclude, node.Line, strings.Join(stack, " -> "), target)
+	}
+
+	b, err := p.read(target)
+	if err != nil {
+		return fmt.Errorf("%w: line %d: %w", ErrInclude, node.Line, err)
+	}
+
+	root, err := parseFragment(b, anchors)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrInclude, target, err)
+	}
+	if root == nil {
+		// an empty file includes null
+		*node = *newNode(yaml.ScalarNode, "!!null", "")
+		return nil
+	}
+
+	anchors = append(slices.Clip(anchors), collectAnchors(root, nil)...)
+	if err := p.resolveTags(root, target, append(slices.Clip(stack), target), anchors); err != nil {
+		return err
+	}
+	*node = *root
+
+	return nil
+}
+
+// parseFragment parses an included file, which may reference the anchors of the including files,
+// e.g. with `<<: *defaults`, also after directives and a `---` marker. It returns nil for an empty file.
+func parseFragment(b []byte, anchors []*yaml.Node) (*yaml.Node, error) {
+	var doc yaml.Node
+	err := yaml.Unmarshal(b, &doc)
+	if err == nil || len(anchors) == 0 {
+		if err != nil || len(doc.Content) == 0 {
+			return nil, err //nolint:wrapcheck // wrapped by the caller
+		}
+		return doc.Content[0], nil
+	}
+
+	// yaml fails on unknown anchors, so parse the file as the last item of a list defining them first
+	list := newNode(yaml.SequenceNode, "!!seq", "")
+	list.Content = anchors
+	preamble, encErr := yaml.Marshal(list)
+	if encErr != nil {
+		return nil, err //nolint:wrapcheck // the original error is more meaningful
+	}
+
+	var wrapped bytes.Buffer
+	wrapped.Write(preamble)
+	for i, line := range fragmentLines(b) {
+		if i == 0 {
+			wrapped.WriteString("- " + line + "\n")
+		} else {
+			wrapped.WriteString("  " + line + "\n")
+		}
+	}
+
+	var wdoc yaml.Node
+	if yaml.Unmarshal(wrapped.Bytes(), &wdoc) != nil || len(wdoc.Content) == 0 {
+		return nil, err //nolint:wrapcheck // the original error points to the right line
+	}
+
+	items := wdoc.Content[0].Content
+	root := items[len(items)-1]
+	shiftLines(root, -bytes.Count(preamble, []byte("\n")))
+
+	return root, nil
+}
+
+// fragmentLines returns the lines of a fragment with its directives, e.g. `%YAML 1.2`, and document
+// markers blanked, so the document can be nested in a list. Line and column positions are kept.
+func fragmentLines(b []byte) []string {
+	lines := strings.Split(string(b), "\n")
+	header := true
+	for i, line := range lines {
+		switch {
+		case header && strings.HasPrefix(line, "%"):
+			lines[i] = ""
+		case header && (line == "---" || strings.HasPrefix(line, "--- ") || strings.HasPrefix(line, "---\t")):
+			lines[i] = "   " + line[3:]
+			header = false
+		case line == "...":
+			lines[i] = ""
+		case header && strings.TrimSpace(line) != "" && !strings.HasPrefix(strings.TrimSpace(line), "#"):
+			header = false
+		}
+	}
+
+	return lines
+}
+
+// collectAnchors appends the anchored nodes of the tree in document order.
+func collectAnchors(node *yaml.Node, anchors []*yaml.Node) []*yaml.Node {
+	if node.Anchor != "" {
+		anchors = append(anchors, node)
+	}
+	for _, child := range node.Content {
+		anchors = collectAnchors(child, anchors)
+	}
+
+	return anchors
+}
+
+// shiftLines moves the positions of the nodes of a tree parsed at an offset back to the original lines.
+func shiftLines(node *yaml.Node, delta int) {
+	node.Line += delta
+	node.Column -= 2
+	for _, child := range node.Content {
+		shiftLines(child, delta)
+	}
+}
+
+// env replaces the node by the value of the named environment variable, as a string.
+func (p yamlParser) env(node *yaml.Node) error {
+	if node.Kind != yaml.ScalarNode || node.Value == "" {
+		return fmt.Errorf("%w: line %d: expected a variable name", ErrEnvNotSet, node.Line)
+	}
+
+	v, ok := p.lookupEnv(node.Value)
+	switch {
+	case ok:
+		*node = *newNode(yaml.ScalarNode, "!!str", v)
+	case p.requireEnv:
+		return fmt.Errorf("%w: line %d: %s", ErrEnvNotSet, node.Line, node.Value)
+	default:
+		*node = *newNode(yaml.ScalarNode, "!!null", "")
+	}
+
+	return nil
+}
+
+// file replaces the node by the contents of the referenced file with a trailing newline trimmed.
+func (p yamlParser) file(node *yaml.Node, file string) error {
+	if node.Kind != yaml.ScalarNode || node.Value == "" {
+		return fmt.Errorf("%w: line %d: expected a file path", ErrFileTag, node.Line)
+	}
+
+	b, err := p.read(relativeTo(file, node.Value))
+	if err != nil {
+		return fmt.Errorf("%w: line %d: %w", ErrFileTag, node.Line, err)
+	}
+
+	s := strings.TrimSuffix(strings.TrimSuffix(string(b), "\n"), "\r")
+	*node = *newNode(yaml.ScalarNode, "!!str", s)
+
+	return nil
+}
+
+// relativeTo resolves path relative to the directory of file, unless absolute.
+func relativeTo(file, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(filepath.Dir(file), path)
+}
+
+// secret replaces the node by the resolved secret.
+func (p yamlParser) secret(node *yaml.Node) error {
+	if node.Kind != yaml.ScalarNode || node.Value == "" {
+		return fmt.Errorf("%w: line %d: expected scheme:ref", ErrSecretRef, node.Line)
+	}
+
+	v, err := p.resolveSecret(node.Value)
+	if err != nil {
+		return fmt.Errorf("line %d: %w", node.Line, err)
+	}
+	*node = *newNode(yaml.ScalarNode, "!!str", v)
+
+	return nil
+}
+
+// resolveSecret resolves a `scheme:ref` secret reference with the resolver registered for scheme.
+func resolveSecret(ctx context.Context, resolvers map[string]SecretResolver, ref string) (string, error) {
+	scheme, rest, ok := strings.Cut(ref, ":")
+	if !ok {
+		return "", fmt.Errorf("%w: %s: expected scheme:ref", ErrSecretRef, ref)
+	}
+
+	resolve, ok := resolvers[scheme]
+	if !ok {
+		return "", fmt.Errorf("%w: %s: no resolver for %q", ErrSecretRef, ref, scheme)
+	}
+
+	v, err := resolve(ctx, rest)
+	if err != nil {
+		return "", fmt.Errorf("resolve secret %s: %w", ref, err)
+	}
+
+	return v, nil
+}
@@ -0,0 +1,226 @@
+package config_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-core-fx/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loadYAML[T any](t *testing.T, c *T, files fstest.MapFS, opts ...config.Option) error {
+	t.Helper()
+
+	return config.Load(c, append([]config.Option{
+		config.WithFS(files),
+		config.WithLocalYAML("config.yaml"),
+		config.WithEnviron(nil),
+	}, opts...)...)
+}
+
+// TestMultiDocumentYAML tests merging documents of one file in order
+func TestMultiDocumentYAML(t *testing.T) {
+	var c TestConfig
+	require.NoError(t, loadYAML(t, &c, fstest.MapFS{"config.yaml": {Data: []byte(`database:
+  host: a
+  port: 5432
+---
+# empty documents are skipped
+---
+database:
+  host: b
+feature_flags:
+  beta: true
+`)}}))
+	assert.Equal(t, "b", c.Database.Host)
+	assert.Equal(t, 5432, c.Database.Port)
+	assert.True(t, c.FeatureFlags["beta"])
+}
+
+// TestMultiDocumentYAMLError tests that errors name the failing document
+func TestMultiDocumentYAMLError(t *testing.T) {
+	var c TestConfig
+	err := loadYAML(t, &c, fstest.MapFS{"config.yaml": {Data: []byte("database:\n  host: a\n---\n- list\n")}})
+	require.ErrorContains(t, err, "document 2")
+}
+
+// TestDuplicateKeys tests failing on keys defined twice in a document
+func TestDuplicateKeys(t *testing.T) {
+	files := fstest.MapFS{"config.yaml": {Data: []byte("database:\n  host: a\n  port: 1\n  host: b\n---\ndatabase:\n  host: c\n")}}
+
+	var c TestConfig
+	err := loadYAML(t, &c, files)
+	require.ErrorIs(t, err, config.ErrDuplicateKey)
+	require.ErrorContains(t, err, "database.host at line 4, already defined at line 2")
+}
+
+// TestDuplicateKeyWarnings tests logging duplicate keys and keeping the last value
+func TestDuplicateKeyWarnings(t *testing.T) {
+	files := fstest.MapFS{"config.yaml": {Data: []byte("feature_flags:\n  a: true\n  a: false\ndatabase:\n  host: a\n  host: b\n")}}
+
+	var buf bytes.Buffer
+	var c TestConfig
+	require.NoError(t, loadYAML(t, &c, files,
+		config.WithDuplicateKeyWarnings(),
+		config.WithLogger(slog.New(slog.NewTextHandler(&buf, nil))),
+	))
+	assert.Equal(t, "b", c.Database.Host)
+	assert.Equal(t, map[string]bool{"a": false}, c.FeatureFlags)
+	assert.Contains(t, buf.String(), `msg="duplicate yaml key, using the last value" key=database.host line=6 previous_line=5`)
+	assert.Contains(t, buf.String(), `key=feature_flags.a line=3 previous_line=2`)
+}
+
+// TestInclude tests replacing !include values by the included files, relative to the including file
+func TestInclude(t *testing.T) {
+	files := fstest.MapFS{
+		"config.yaml":          {Data: []byte("database: !include conf.d/database.yaml\nfeature_flags: !include conf.d/empty.yaml\n")},
+		"conf.d/database.yaml": {Data: []byte("<<: !include defaults.yaml\nhost: db\n")},
+		"conf.d/defaults.yaml": {Data: []byte("host: localhost\nport: 5432\n")},
+		"conf.d/empty.yaml":    {Data: []byte("")},
+	}
+
+	var c TestConfig
+	require.NoError(t, loadYAML(t, &c, files))
+	assert.Equal(t, "db", c.Database.Host)
+	assert.Equal(t, 5432, c.Database.Port)
+	assert.Nil(t, c.FeatureFlags)
+}
+
+// TestIncludeErrors tests missing files, include cycles and invalid include values
+func TestIncludeErrors(t *testing.T) {
+	tests := map[string]fstest.MapFS{
+		"missing": {"config.yaml": {Data: []byte("database: !include missing.yaml\n")}},
+		"cycle": {
+			"config.yaml": {Data: []byte("database: !include a.yaml\n")},
+			"a.yaml":      {Data: []byte("host: !include config.yaml\n")},
+		},
+		"not a path": {"config.yaml": {Data: []byte("database: !include [a.yaml]\n")}},
+	}
+
+	for name, files := range tests {
+		t.Run(name, func(t *testing.T) {
+			var c TestConfig
+			require.ErrorIs(t, loadYAML(t, &c, files), config.ErrInclude)
+		})
+	}
+}
+
+// TestEnvTag tests resolving !env values from the environment
+func TestEnvTag(t *testing.T) {
+	files := fstest.MapFS{"config.yaml": {Data: []byte("database:\n  host: !env DB_HOST\n  port: !env DB_PORT\n  password: !env DB_PASSWORD\n")}}
+	environ := config.WithEnviron(map[string]string{"DB_HOST": "db", "DB_PORT": "5432"})
+
+	var c TestConfig
+	require.NoError(t, loadYAML(t, &c, files, environ))
+	assert.Equal(t, "db", c.Database.Host)
+	assert.Equal(t, 5432, c.Database.Port)
+	assert.Empty(t, c.Database.Password)
+
+	err := loadYAML(t, &c, files, environ, config.WithRequiredEnvTags())
+	require.ErrorIs(t, err, config.ErrEnvNotSet)
+	require.ErrorContains(t, err, "line 4: DB_PASSWORD")
+}
+
+// TestFileTag tests inlining !file contents as strings, relative to the YAML file
+func TestFileTag(t *testing.T) {
+	files := fstest.MapFS{
+		"conf/config.yaml":  {Data: []byte("database:\n  password: !file secrets/db\n  port: !file secrets/port\n")},
+		"conf/secrets/db":   {Data: []byte("s3cr3t\n")},
+		"conf/secrets/port": {Data: []byte("5432")},
+	}
+
+	var c TestConfig
+	require.NoError(t, config.Load(&c, config.WithFS(files), config.WithLocalYAML("conf/config.yaml"), config.WithEnviron(nil)))
+	assert.Equal(t, "s3cr3t", c.Database.Password)
+	assert.Equal(t, 5432, c.Database.Port)
+
+	files["conf/config.yaml"] = &fstest.MapFile{Data: []byte("database:\n  password: !file secrets/missing\n")}
+	err := config.Load(&c, config.WithFS(files), config.WithLocalYAML("conf/config.yaml"), config.WithEnviron(nil))
+	require.ErrorIs(t, err, config.ErrFileTag)
+}
+
+// TestSecretTag tests resolving !secret references with registered resolvers
+func TestSecretTag(t *testing.T) {
+	files := fstest.MapFS{"config.yaml": {Data: []byte("database:\n  password: !secret vault:kv/app#db_password\n")}}
+	vault := config.WithSecretResolver("vault", func(_ context.Context, ref string) (string, error) {
+		if ref != "kv/app#db_password" {
+			return "", errUnavailable
+		}
+		return "s3cr3t", nil
+	})
+
+	var c TestConfig
+	require.NoError(t, loadYAML(t, &c, files, vault))
+	assert.Equal(t, "s3cr3t", c.Database.Password)
+
+	err := loadYAML(t, &c, files)
+	require.ErrorIs(t, err, config.ErrSecretRef)
+	require.ErrorContains(t, err, `no resolver for "vault"`)
+
+	files["config.yaml"] = &fstest.MapFile{Data: []byte("database:\n  password: !secret vault:kv/other#password\n")}
+	require.ErrorIs(t, loadYAML(t, &c, files, vault), errUnavailable)
+}
+
+// TestIncludeAnchors tests referencing anchors of the including file from included files
+func TestIncludeAnchors(t *testing.T) {
+	files := fstest.MapFS{
+		"config.yaml": {Data: []byte(`
+shared:
+  db: &db
+    host: shared-db
+    port: 5432
+  flags: &flags !include flags.yaml
+database: !include database.yaml
+`)},
+		"database.yaml": {Data: []byte("<<: *db\nusername: app\n")},
+		"flags.yaml":    {Data: []byte("new_checkout: true\n")},
+		"server.yaml":   {Data: []byte("port: *port\n")},
+	}
+
+	var c TestConfig
+	require.NoError(t, loadYAML(t, &c, files))
+	assert.Equal(t, "shared-db", c.Database.Host)
+	assert.Equal(t, 5432, c.Database.Port)
+	assert.Equal(t, "app", c.Database.Username)
+
+	// included files pass anchors on to the files they include, and see included anchored blocks
+	files["config.yaml"] = &fstest.MapFile{Data: []byte(`
+shared:
+  flags: &flags !include flags.yaml
+  port: &port 8080
+feature_flags: !include feature_flags.yaml
+server: !include nested.yaml
+`)}
+	files["feature_flags.yaml"] = &fstest.MapFile{Data: []byte("<<: *flags\ndark_mode: false\n")}
+	files["nested.yaml"] = &fstest.MapFile{Data: []byte("!include server.yaml\n")}
+
+	c = TestConfig{}
+	require.NoError(t, loadYAML(t, &c, files))
+	assert.Equal(t, map[string]bool{"new_checkout": true, "dark_mode": false}, c.FeatureFlags)
+	assert.Equal(t, 8080, c.Server.Port)
+
+	// included files may start with directives and a document marker
+	files["config.yaml"] = &fstest.MapFile{Data: []byte("defaults: &defaults\n  host: shared-db\ndatabase: !include database.yaml\n")}
+	files["database.yaml"] = &fstest.MapFile{Data: []byte("%YAML 1.2\n---\n<<: *defaults\nport: 5432\n...\n")}
+	c = TestConfig{}
+	require.NoError(t, loadYAML(t, &c, files))
+	assert.Equal(t, "shared-db", c.Database.Host)
+	assert.Equal(t, 5432, c.Database.Port)
+
+	files["database.yaml"] = &fstest.MapFile{Data: []byte("# database\n--- {<<: *defaults, port: 6432}\n")}
+	c = TestConfig{}
+	require.NoError(t, loadYAML(t, &c, files))
+	assert.Equal(t, "shared-db", c.Database.Host)
+	assert.Equal(t, 6432, c.Database.Port)
+
+	// unknown anchors still fail with the position in the included file
+	files["database.yaml"] = &fstest.MapFile{Data: []byte("username: app\n<<: *missing\n")}
+	files["config.yaml"] = &fstest.MapFile{Data: []byte("shared: &db {}\ndatabase: !include database.yaml\n")}
+	err := loadYAML(t, &c, files)
+	require.ErrorIs(t, err, config.ErrInclude)
+	assert.Contains(t, err.Error(), "missing")
+}